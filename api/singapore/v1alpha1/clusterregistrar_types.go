@@ -3,6 +3,7 @@
 package v1alpha1
 
 import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -10,12 +11,66 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+const (
+	// ClusterRegistrarConditionOperatorDeployed is true when the compute-operator Deployment is applied and available.
+	ClusterRegistrarConditionOperatorDeployed string = "OperatorDeployed"
+
+	// ClusterRegistrarConditionWebhookDeployed is true when the webhook Deployment is applied and available.
+	ClusterRegistrarConditionWebhookDeployed string = "WebhookDeployed"
+
+	// ClusterRegistrarConditionAPIServiceAvailable is true when the APIService of the webhook is available.
+	ClusterRegistrarConditionAPIServiceAvailable string = "APIServiceAvailable"
+
+	// ClusterRegistrarConditionUpgradeFailed is true when the compute-operator Deployment did not roll out a new image.
+	ClusterRegistrarConditionUpgradeFailed string = "UpgradeFailed"
+)
+
 // ClusterRegistrarSpec defines the desired state of ClusterRegistrar
 type ClusterRegistrarSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make generate" to regenerate code after modifying this file
 
 	ComputeService ComputeService `json:"computeService"`
+
+	// OperatorImagePullPolicy is the pull policy of the compute-operator image. If empty, Always is used for
+	// the latest tag and IfNotPresent for the other tags.
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	OperatorImagePullPolicy corev1.PullPolicy `json:"operatorImagePullPolicy,omitempty"`
+
+	// OperatorResources overrides the default resource requests and limits of the compute-operator container.
+	// The requests and limits not set keep their default value.
+	// +optional
+	OperatorResources *corev1.ResourceRequirements `json:"operatorResources,omitempty"`
+
+	// OperatorLeaderElection configures the leader election of the compute-operator, it is enabled with the
+	// default durations if not set.
+	// +optional
+	OperatorLeaderElection *LeaderElectionConfig `json:"operatorLeaderElection,omitempty"`
+
+	// WebhookFailurePolicy is the failurePolicy of the RegisteredCluster validating webhook. With Fail, a
+	// RegisteredCluster can not be created or updated while the webhook is unavailable, for example during an
+	// install or an upgrade. With Ignore, the requests are admitted unvalidated while the webhook is unavailable.
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	// +kubebuilder:default=Fail
+	// +optional
+	WebhookFailurePolicy admissionregistrationv1.FailurePolicyType `json:"webhookFailurePolicy,omitempty"`
+}
+
+// LeaderElectionConfig contains the leader election configuration of the compute-operator
+type LeaderElectionConfig struct {
+	// Disabled disables the leader election, only safe with a single replica of the compute-operator.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// LeaseDuration is the time the non-leader candidates wait before acquiring the lease, 15s if not set.
+	// +optional
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+
+	// RenewDeadline is the time the leader retries renewing the lease before giving it up, 10s if not set.
+	// It must be shorter than the LeaseDuration.
+	// +optional
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
 }
 
 // ComputeService contains information about the compute service
@@ -10,6 +10,14 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+const (
+	// HubConfigConditionValid is true when the kubeconfig secret of the HubConfig
+	// exists, parses as a kubeconfig and the hub is reachable.
+	HubConfigConditionValid string = "HubConfigValid"
+	// HubConfigConditionReachable is true when the last periodic check of the hub API succeeded.
+	HubConfigConditionReachable string = "HubReachable"
+)
+
 // HubConfigSpec defines the desired state of HubConfig
 type HubConfigSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -25,6 +33,20 @@ type HubConfigSpec struct {
 	// If it's zero, the created Client will use DefaultQPS: 100.0
 	// +optional
 	QPS string `json:"QPS,omitempty"`
+
+	// SyncerImagePullSecretRef is a kubernetes.io/dockerconfigjson secret of the HubConfig namespace, delivered
+	// with the kcp-syncer of the RegisteredClusters of the hub to pull the kcp-syncer image.
+	// +optional
+	SyncerImagePullSecretRef corev1.LocalObjectReference `json:"syncerImagePullSecretRef,omitempty"`
+
+	// SyncerTemplatesRef is a ConfigMap of the HubConfig namespace holding kcp-syncer manifestwork templates,
+	// keyed by template name. A RegisteredCluster of the hub selects one with its SyncerTemplate.
+	// +optional
+	SyncerTemplatesRef corev1.LocalObjectReference `json:"syncerTemplatesRef,omitempty"`
+
+	// SyncerProxy is the proxy configuration of the kcp-syncer of the RegisteredClusters of the hub.
+	// +optional
+	SyncerProxy *SyncerProxyConfig `json:"syncerProxy,omitempty"`
 }
 
 // HubConfigStatus defines the observed state of HubConfig
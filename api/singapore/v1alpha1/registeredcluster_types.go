@@ -8,6 +8,56 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 )
 
+const (
+	// RegisteredClusterConditionSyncerEvicted is true when the kcp-syncer is removed
+	// from the cluster because the ManagedCluster has an eviction taint.
+	RegisteredClusterConditionSyncerEvicted string = "SyncerEvicted"
+
+	// RegisteredClusterConditionManagedClusterAdopted reports the adoption of an existing ManagedCluster,
+	// it is false if the ManagedCluster already belongs to another RegisteredCluster.
+	RegisteredClusterConditionManagedClusterAdopted string = "ManagedClusterAdopted"
+
+	// RegisteredClusterConditionDeletionProtected is true when the deletion of the RegisteredCluster
+	// is refused because it has the deletion protection annotation.
+	RegisteredClusterConditionDeletionProtected string = "DeletionProtected"
+
+	// RegisteredClusterConditionHubReachable is false when the hub of the RegisteredCluster is not usable,
+	// its clients are not set or it can't be reached.
+	RegisteredClusterConditionHubReachable string = "HubReachable"
+
+	// RegisteredClusterConditionImportSecretGenerated is false when the hub didn't generate the import secret
+	// of the ManagedCluster within the import secret timeout.
+	RegisteredClusterConditionImportSecretGenerated string = "ImportSecretGenerated"
+
+	// RegisteredClusterConditionInvalidLocation is true when a location workspace of the RegisteredCluster
+	// can not be resolved on the compute server, the RegisteredCluster is not processed until it is fixed.
+	RegisteredClusterConditionInvalidLocation string = "InvalidLocation"
+
+	// RegisteredClusterConditionKcpServerValid is false when the kcp server URL given to the kcp-syncer
+	// doesn't look like a well-formed https URL.
+	RegisteredClusterConditionKcpServerValid string = "KcpServerValid"
+
+	// RegisteredClusterConditionProvisioned is false with the Pending reason from the first reconcile
+	// until the RegisteredCluster is fully reconciled.
+	RegisteredClusterConditionProvisioned string = "Provisioned"
+
+	// RegisteredClusterConditionReady is true when the cluster joined and its kcp-syncer is applied and available,
+	// it aggregates the conditions of the reconcile phases.
+	RegisteredClusterConditionReady string = "Ready"
+
+	// RegisteredClusterConditionSyncerAvailable is true when the kcp-syncer manifestwork is applied
+	// and its resources are available on the cluster.
+	RegisteredClusterConditionSyncerAvailable string = "SyncerAvailable"
+
+	// RegisteredClusterConditionSyncerVersionCompatible is false when the kcp-syncer image version is
+	// known to be incompatible with the kcp server version, and unknown when a version can't be determined.
+	RegisteredClusterConditionSyncerVersionCompatible string = "SyncerVersionCompatible"
+
+	// RegisteredClusterConditionThrottled is true when the reconcile of the RegisteredCluster is slowed
+	// down by an apiserver backpressure, usually the hub one.
+	RegisteredClusterConditionThrottled string = "Throttled"
+)
+
 // RegisteredClusterSpec defines the desired state of RegisteredCluster
 type RegisteredClusterSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -16,6 +66,99 @@ type RegisteredClusterSpec struct {
 	// kcp workspaces where SyncTarget will be created
 	// +kubebuilder:validation:Required
 	Location []string `json:"location,omitempty"`
+
+	// DeploySyncer deploys the kcp-syncer of the locations on the cluster. If false, the cluster is only
+	// registered for inventory and status, no SyncTarget nor kcp-syncer is created. If not set, true is used.
+	// +optional
+	// +kubebuilder:default=true
+	DeploySyncer *bool `json:"deploySyncer,omitempty"`
+
+	// InitialClusterClaims are seeded on the ManagedCluster at import, before the agent reports its own claims.
+	// The claims reported by the agent take precedence over the initial claims with the same name.
+	// +optional
+	InitialClusterClaims []clusterv1.ManagedClusterClaim `json:"initialClusterClaims,omitempty"`
+
+	// LeaseDurationSeconds overrides the lease duration of the ManagedCluster. A longer lease avoids
+	// a cluster on a flaky network to be marked unavailable. If zero, the hub default is used.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds,omitempty"`
+
+	// ManagedClusterAnnotations are added to the ManagedCluster when it is created, for example for a cost center
+	// or an environment. The annotations reserved by the operator can't be overwritten.
+	// +optional
+	ManagedClusterAnnotations map[string]string `json:"managedClusterAnnotations,omitempty"`
+
+	// SyncerDNSConfig is the DNS configuration of the kcp-syncer pod, for clusters needing
+	// custom nameservers, search domains or ndots to resolve the kcp endpoint.
+	// +optional
+	SyncerDNSConfig *corev1.PodDNSConfig `json:"syncerDNSConfig,omitempty"`
+
+	// SyncerImage is the kcp-syncer image of the RegisteredCluster, for example to stage a syncer rollout.
+	// If empty, the image of the syncer channel, the operator KCP_SYNCER_IMAGE or the default image is used.
+	// +optional
+	SyncerImage string `json:"syncerImage,omitempty"`
+
+	// SyncerImagePullSecretRef is a kubernetes.io/dockerconfigjson secret of the RegisteredCluster namespace,
+	// delivered with the kcp-syncer to pull the kcp-syncer image. It takes precedence over the HubConfig one.
+	// +optional
+	SyncerImagePullSecretRef corev1.LocalObjectReference `json:"syncerImagePullSecretRef,omitempty"`
+
+	// SyncerNodeSelector constrains the kcp-syncer pod to the nodes with the given labels.
+	// +optional
+	SyncerNodeSelector map[string]string `json:"syncerNodeSelector,omitempty"`
+
+	// SyncerProxy is the proxy configuration of the kcp-syncer, for clusters reaching the kcp server
+	// through a proxy. It takes precedence over the HubConfig one.
+	// +optional
+	SyncerProxy *SyncerProxyConfig `json:"syncerProxy,omitempty"`
+
+	// SyncerReplicas is the number of kcp-syncer replicas. More than one replica requires a kcp-syncer
+	// image supporting leader election, else the replicas sync concurrently. If not set, 1 is used.
+	// +optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	SyncerReplicas *int32 `json:"syncerReplicas,omitempty"`
+
+	// SyncerResources overrides the default resource requests and limits of the kcp-syncer container,
+	// 50m CPU and 64Mi memory requested, 500m CPU and 512Mi memory limits. The requests and limits not
+	// set keep their default value.
+	// +optional
+	SyncerResources *corev1.ResourceRequirements `json:"syncerResources,omitempty"`
+
+	// SyncerTemplate selects the kcp-syncer manifestwork template. If empty, the bundled default
+	// template is used, it sets the SyncerResources requests and limits on the kcp-syncer. "limited",
+	// which used to be the only template with limits, is an alias of the default template. Any other
+	// name selects a template of the HubConfig SyncerTemplatesRef ConfigMap.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9-]*$`
+	SyncerTemplate string `json:"syncerTemplate,omitempty"`
+
+	// SyncerTolerations are the tolerations of the kcp-syncer pod, to schedule it on tainted nodes.
+	// +optional
+	SyncerTolerations []corev1.Toleration `json:"syncerTolerations,omitempty"`
+
+	// SyncerServiceAccountNamespace is the namespace of the location workspaces holding the kcp-syncer
+	// ServiceAccount and its token. The namespace must exist. If empty, "default" is used.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	SyncerServiceAccountNamespace string `json:"syncerServiceAccountNamespace,omitempty"`
+}
+
+// SyncerProxyConfig is the proxy configuration of the kcp-syncer, set as environment variables of
+// the kcp-syncer container. The empty values are not set.
+type SyncerProxyConfig struct {
+	// HTTPProxy is the proxy URL of the HTTP requests, set as HTTP_PROXY.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the proxy URL of the HTTPS requests, set as HTTPS_PROXY.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts, domains and CIDRs not proxied, set as NO_PROXY.
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
 }
 
 // RegisteredClusterStatus defines the observed state of RegisteredCluster
@@ -23,8 +166,13 @@ type RegisteredClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make generate" to regenerate code after modifying this file
 
-	//ImportCommandRef is reference to configmap containing import command.
-	ImportCommandRef corev1.LocalObjectReference `json:"importCommandRef,omitempty"`
+	//ImportCommandRef is reference to the secret containing import command.
+	ImportCommandRef corev1.SecretReference `json:"importCommandRef,omitempty"`
+
+	// ImportManifestsRef is a reference to the secret containing the import manifests in its crds.yaml and
+	// import.yaml keys, to apply them with other tooling than the import command, for example GitOps.
+	// +optional
+	ImportManifestsRef corev1.SecretReference `json:"importManifestsRef,omitempty"`
 
 	// ClusterID uniquely identifies this registered cluster
 	ClusterID string `json:"clusterID,omitempty"`
@@ -60,6 +208,62 @@ type RegisteredClusterStatus struct {
 	//ApiURL the URL of apiserver endpoint of the registered cluster.
 	// +optional
 	ApiURL string `json:"apiURL,omitempty"`
+
+	// ObservedGeneration is the generation of the RegisteredCluster last successfully reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LocationPath contains the fully qualified workspace paths of the locations.
+	// +optional
+	LocationPath []string `json:"locationPath,omitempty"`
+
+	// SyncerLastAppliedTime is the last time the kcp-syncer manifestwork was successfully applied.
+	// +optional
+	SyncerLastAppliedTime *metav1.Time `json:"syncerLastAppliedTime,omitempty"`
+
+	// ConsoleURL is the URL of the console of the registered cluster, as reported by the hub.
+	// +optional
+	ConsoleURL string `json:"consoleURL,omitempty"`
+
+	// HostingCluster is the name of the management cluster hosting the klusterlet of the registered
+	// cluster, set only when the klusterlet is deployed in hosted mode.
+	// +optional
+	HostingCluster string `json:"hostingCluster,omitempty"`
+
+	// KcpServer is the kcp server URL given to the kcp-syncer.
+	// +optional
+	KcpServer string `json:"kcpServer,omitempty"`
+
+	// SyncerImage is the kcp-syncer image resolved for the RegisteredCluster.
+	// +optional
+	SyncerImage string `json:"syncerImage,omitempty"`
+
+	// SyncerTokens are the ServiceAccount tokens injected in the kcp-syncer manifestworks, one per location.
+	// +optional
+	SyncerTokens []SyncerTokenStatus `json:"syncerTokens,omitempty"`
+}
+
+// SyncerTokenStatus is the ServiceAccount token injected in the kcp-syncer manifestwork of a location.
+type SyncerTokenStatus struct {
+	// LocationPath is the fully qualified workspace path of the location.
+	LocationPath string `json:"locationPath"`
+
+	// SecretName is the name of the ServiceAccount token secret in the location workspace.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// TokenHash is the SHA-256 hash of the token, to detect its rotation without exposing it.
+	// +optional
+	TokenHash string `json:"tokenHash,omitempty"`
+
+	// LastInjectedTime is the last time the token was injected in the kcp-syncer manifestwork.
+	// +optional
+	LastInjectedTime *metav1.Time `json:"lastInjectedTime,omitempty"`
+
+	// ExpirationTime is the expiration time of the token, set only for a bound token. The token is renewed
+	// and injected again before it expires.
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
 }
 
 // +genclient
@@ -8,6 +8,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
@@ -18,7 +19,7 @@ func (in *ClusterRegistrar) DeepCopyInto(out *ClusterRegistrar) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -76,6 +77,16 @@ func (in *ClusterRegistrarList) DeepCopyObject() runtime.Object {
 func (in *ClusterRegistrarSpec) DeepCopyInto(out *ClusterRegistrarSpec) {
 	*out = *in
 	out.ComputeService = in.ComputeService
+	if in.OperatorResources != nil {
+		in, out := &in.OperatorResources, &out.OperatorResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperatorLeaderElection != nil {
+		in, out := &in.OperatorLeaderElection, &out.OperatorLeaderElection
+		*out = new(LeaderElectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRegistrarSpec.
@@ -189,6 +200,13 @@ func (in *HubConfigList) DeepCopyObject() runtime.Object {
 func (in *HubConfigSpec) DeepCopyInto(out *HubConfigSpec) {
 	*out = *in
 	out.KubeConfigSecretRef = in.KubeConfigSecretRef
+	out.SyncerImagePullSecretRef = in.SyncerImagePullSecretRef
+	if in.SyncerProxy != nil {
+		in, out := &in.SyncerProxy, &out.SyncerProxy
+		*out = new(SyncerProxyConfig)
+		**out = **in
+	}
+	out.SyncerTemplatesRef = in.SyncerTemplatesRef
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HubConfigSpec.
@@ -223,6 +241,31 @@ func (in *HubConfigStatus) DeepCopy() *HubConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionConfig) DeepCopyInto(out *LeaderElectionConfig) {
+	*out = *in
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewDeadline != nil {
+		in, out := &in.RenewDeadline, &out.RenewDeadline
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionConfig.
+func (in *LeaderElectionConfig) DeepCopy() *LeaderElectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegisteredCluster) DeepCopyInto(out *RegisteredCluster) {
 	*out = *in
@@ -290,6 +333,58 @@ func (in *RegisteredClusterSpec) DeepCopyInto(out *RegisteredClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DeploySyncer != nil {
+		in, out := &in.DeploySyncer, &out.DeploySyncer
+		*out = new(bool)
+		**out = **in
+	}
+	if in.InitialClusterClaims != nil {
+		in, out := &in.InitialClusterClaims, &out.InitialClusterClaims
+		*out = make([]clusterv1.ManagedClusterClaim, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedClusterAnnotations != nil {
+		in, out := &in.ManagedClusterAnnotations, &out.ManagedClusterAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SyncerDNSConfig != nil {
+		in, out := &in.SyncerDNSConfig, &out.SyncerDNSConfig
+		*out = new(corev1.PodDNSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	out.SyncerImagePullSecretRef = in.SyncerImagePullSecretRef
+	if in.SyncerNodeSelector != nil {
+		in, out := &in.SyncerNodeSelector, &out.SyncerNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SyncerProxy != nil {
+		in, out := &in.SyncerProxy, &out.SyncerProxy
+		*out = new(SyncerProxyConfig)
+		**out = **in
+	}
+	if in.SyncerReplicas != nil {
+		in, out := &in.SyncerReplicas, &out.SyncerReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SyncerResources != nil {
+		in, out := &in.SyncerResources, &out.SyncerResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncerTolerations != nil {
+		in, out := &in.SyncerTolerations, &out.SyncerTolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegisteredClusterSpec.
@@ -306,6 +401,7 @@ func (in *RegisteredClusterSpec) DeepCopy() *RegisteredClusterSpec {
 func (in *RegisteredClusterStatus) DeepCopyInto(out *RegisteredClusterStatus) {
 	*out = *in
 	out.ImportCommandRef = in.ImportCommandRef
+	out.ImportManifestsRef = in.ImportManifestsRef
 	out.ClusterSecretRef = in.ClusterSecretRef
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -334,6 +430,22 @@ func (in *RegisteredClusterStatus) DeepCopyInto(out *RegisteredClusterStatus) {
 		*out = make([]clusterv1.ManagedClusterClaim, len(*in))
 		copy(*out, *in)
 	}
+	if in.LocationPath != nil {
+		in, out := &in.LocationPath, &out.LocationPath
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SyncerLastAppliedTime != nil {
+		in, out := &in.SyncerLastAppliedTime, &out.SyncerLastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SyncerTokens != nil {
+		in, out := &in.SyncerTokens, &out.SyncerTokens
+		*out = make([]SyncerTokenStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegisteredClusterStatus.
@@ -345,3 +457,41 @@ func (in *RegisteredClusterStatus) DeepCopy() *RegisteredClusterStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncerProxyConfig) DeepCopyInto(out *SyncerProxyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncerProxyConfig.
+func (in *SyncerProxyConfig) DeepCopy() *SyncerProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncerProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncerTokenStatus) DeepCopyInto(out *SyncerTokenStatus) {
+	*out = *in
+	if in.LastInjectedTime != nil {
+		in, out := &in.LastInjectedTime, &out.LastInjectedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncerTokenStatus.
+func (in *SyncerTokenStatus) DeepCopy() *SyncerTokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncerTokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
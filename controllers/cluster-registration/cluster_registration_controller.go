@@ -4,26 +4,34 @@ package registeredcluster
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"net/url"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
-	"github.com/ghodss/yaml"
 	"github.com/go-logr/logr"
 	giterrors "github.com/pkg/errors"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 
 	// corev1 "k8s.io/api/core/v1"
 	workloadv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/workload/v1alpha1"
@@ -37,10 +45,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	manifestworkv1 "open-cluster-management.io/api/work/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -53,6 +63,7 @@ import (
 
 // +kubebuilder:rbac:groups="",resources={secrets},verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups="singapore.open-cluster-management.io",resources={hubconfigs},verbs=get;list;watch
+// +kubebuilder:rbac:groups="singapore.open-cluster-management.io",resources={hubconfigs/status},verbs=update;patch
 // +kubebuilder:rbac:groups="singapore.open-cluster-management.io",resources={registeredclusters},verbs=get;list;watch;create;update;delete
 
 // +kubebuilder:rbac:groups="singapore.open-cluster-management.io",resources={registeredclusters/status},verbs=update;patch
@@ -67,20 +78,72 @@ const (
 	RegisteredClusterUidLabel       string = "registeredcluster.singapore.open-cluster-management.io/uid"
 	ClusterNameAnnotation           string = "registeredcluster.singapore.open-cluster-management.io/clustername"
 	ManagedClusterSetlabel          string = "cluster.open-cluster-management.io/clusterset"
+	HubCAHashAnnotation             string = "registeredcluster.singapore.open-cluster-management.io/hub-ca-hash"
+	ForceResyncAnnotation           string = "singapore.open-cluster-management.io/force-resync"
+	AdoptManagedClusterAnnotation   string = "registeredcluster.singapore.open-cluster-management.io/adopt-managedcluster"
+	SyncerChannelAnnotation         string = "registeredcluster.singapore.open-cluster-management.io/syncer-channel"
+	HostingClusterAnnotation        string = "import.open-cluster-management.io/hosting-cluster-name"
+	DeletionProtectionAnnotation    string = "singapore.open-cluster-management.io/deletion-protection"
 )
 
+// The period to check the import secret of a not yet joined cluster against the hub CA
+const importSecretResyncPeriod = 10 * time.Minute
+
+// The period to check the kcp-syncer token of a joined cluster for a rotation
+const syncerTokenResyncPeriod = 10 * time.Minute
+
+const (
+	// defaultClusterDeletionRequeue is the delay before the deletion of the ManagedCluster is checked again
+	defaultClusterDeletionRequeue = 5 * time.Second
+	// defaultWorkDeletionRequeue is the delay before the deletion of the kcp-syncer manifestworks is checked again
+	defaultWorkDeletionRequeue = 1 * time.Second
+	// importSecretDeletionRequeue is the delay before the deletion of a finalized import secret is checked again
+	importSecretDeletionRequeue = 1 * time.Second
+	// defaultReconcileTimeout is the deadline of the compute and hub API calls of a reconcile
+	defaultReconcileTimeout = 2 * time.Minute
+	// statusWriteTimeout is the deadline of the status writes deferred to the end of a reconcile
+	statusWriteTimeout = 10 * time.Second
+)
+
+// deletionProtectionResyncPeriod is the period the deletion of a protected RegisteredCluster is checked again,
+// the removal of the annotation also triggers a reconcile.
+const deletionProtectionResyncPeriod = 5 * time.Minute
+
 const defaultSyncerImage = "ghcr.io/kcp-dev/kcp/syncer:v0.6.1"
 
+// legacySyncerServiceAccountName is the ServiceAccount shared by the kcp-syncers of a location workspace before
+// each SyncTarget got its own, it is deleted once the kcp-syncer is given the token of its own ServiceAccount.
+const legacySyncerServiceAccountName = "kcp-syncer-sa"
+
+// The default resource requests and limits of the kcp-syncer container
+const (
+	defaultSyncerCPURequest    = "50m"
+	defaultSyncerMemoryRequest = "64Mi"
+	defaultSyncerCPULimit      = "500m"
+	defaultSyncerMemoryLimit   = "512Mi"
+)
+
 var syncTargetGVR = schema.GroupVersionResource{
 	Group:    "workload.kcp.dev",
 	Version:  "v1alpha1",
 	Resource: "synctargets",
 }
 
+var defaultImportCommandTemplate = template.Must(helpers.ParseImportCommandTemplate(helpers.DefaultImportCommandTemplate))
+
+var registeredClusterGVR = singaporev1alpha1.SchemeGroupVersion.WithResource("registeredclusters")
+
+var managedClusterInfoGVK = schema.GroupVersionKind{
+	Group:   "internal.open-cluster-management.io",
+	Version: "v1beta1",
+	Kind:    "ManagedClusterInfo",
+}
+
 // RegisteredClusterReconciler reconciles a RegisteredCluster object
 type RegisteredClusterReconciler struct {
 	client.Client
-	// KubeClient         kubernetes.Interface
+	// KubeClient is the client of the cluster running the operator, where the HubConfigs are.
+	KubeClient kubernetes.Interface
 	// DynamicClient      dynamic.Interface
 	// APIExtensionClient apiextensionsclient.Interface
 	ComputeConfig             *rest.Config
@@ -90,23 +153,290 @@ type RegisteredClusterReconciler struct {
 	//KCPClusterClient          *kcpclient.Cluster
 	Log         logr.Logger
 	Scheme      *runtime.Scheme
+	Recorder    record.EventRecorder
 	HubClusters []helpers.HubInstance
+	// ImportSecretNamespace is the compute namespace where the import secret is created.
+	// If empty, the import secret is created in the RegisteredCluster namespace.
+	ImportSecretNamespace string
+	// ImportSecretLabels are the labels added to the import secret.
+	ImportSecretLabels map[string]string
+	// SyncerFailureEvents enables warning events on the RegisteredCluster when the kcp-syncer is unavailable.
+	SyncerFailureEvents bool
+	// DeleteClusterSetBindings enables the deletion of the workspace ManagedClusterSetBindings
+	// when the last RegisteredCluster of the workspace is deleted.
+	DeleteClusterSetBindings bool
+	// PropagateConsoleURL enables the copy of the ManagedClusterInfo console URL in the RegisteredCluster status.
+	PropagateConsoleURL bool
+	// ImportApplyRetries is the number of retries of the import secret apply on a transient error.
+	ImportApplyRetries int
+	// DeleteDuplicateClusters deletes the not joined ManagedClusters of a RegisteredCluster other than the
+	// oldest one, duplicates can be created when the ManagedCluster creation races on a slow hub.
+	DeleteDuplicateClusters bool
+	// BatchStatusUpdates accumulates the status changes of a reconcile and writes them with a single patch
+	// at the end of the reconcile. It is enabled by the operator unless --batch-status-updates=false.
+	BatchStatusUpdates bool
+	// ImportSecretTimeout is the time after the ManagedCluster creation after which a missing import secret
+	// is reported in the ImportSecretGenerated condition, disabled if zero.
+	ImportSecretTimeout time.Duration
+	// ImportCommandTemplate is the template of the import command, helpers.DefaultImportCommandTemplate if nil.
+	ImportCommandTemplate *template.Template
+	// ImportCommandCLI is the command line applying the import resources in the import command, kubectl if empty.
+	ImportCommandCLI string
+	// ImportCommandSleep is the pause of the import command between the crds and the import resources applies.
+	ImportCommandSleep time.Duration
+	// ProvisionedCondition enables the Provisioned condition, set to Pending on the first reconcile.
+	ProvisionedCondition bool
+	// ClusterDeletionRequeue is the delay before the deletion of the ManagedCluster of a deleted RegisteredCluster
+	// is checked again, defaultClusterDeletionRequeue if zero.
+	ClusterDeletionRequeue time.Duration
+	// WorkDeletionRequeue is the delay before the deletion of the kcp-syncer manifestworks is checked again,
+	// defaultWorkDeletionRequeue if zero.
+	WorkDeletionRequeue time.Duration
+	// ReconcileTimeout is the deadline of the compute and hub API calls of a reconcile, defaultReconcileTimeout
+	// if zero.
+	ReconcileTimeout time.Duration
+	// SyncerTokenExpiration is the expiration of the bound tokens minted for the kcp-syncer ServiceAccounts
+	// without legacy token secret, helpers.DefaultSyncerTokenExpiration if zero.
+	SyncerTokenExpiration time.Duration
+	// SyncerChannelImages maps the syncer channels, set with the SyncerChannelAnnotation, to kcp-syncer images.
+	SyncerChannelImages map[string]string
+	// Backoff computes the requeue delays, the delays are not backed off nor jittered if nil.
+	Backoff *helpers.RequeueBackoff
+	// SyncerEvictionTaints are the ManagedCluster taint keys which remove the kcp-syncer from the cluster.
+	SyncerEvictionTaints []string
+	// ManifestWorkNamespaceFunc returns the hub namespace of the kcp-syncer manifestwork for a ManagedCluster.
+	// If nil, the manifestwork is created in the ManagedCluster namespace.
+	ManifestWorkNamespaceFunc func(managedCluster *clusterapiv1.ManagedCluster) string
+
+	// hubClustersMutex protects HubClusters, hubs are added while the controller runs by addHubCluster
+	hubClustersMutex sync.RWMutex
+	// controller is the RegisteredCluster controller, used to watch the hubs added after the start
+	controller controller.Controller
+	// hubEvents enqueues the RegisteredClusters of a hub whose health changed
+	hubEvents chan event.GenericEvent
+	// kcpServer is the base URL of the kcp server given to the kcp-syncer, parsed once from the ComputeConfig without
+	// the path of its APIExport virtual workspace
+	kcpServer string
+	// serverVersions caches the kcp server version of the location workspaces
+	serverVersions helpers.ServerVersionCache
+	// servedResources caches the discovery of the SyncTarget resource in the location workspaces
+	servedResources helpers.ResourceServedCache
+	// legacySyncerServiceAccountsDeleted records the location workspace namespaces whose legacy kcp-syncer
+	// ServiceAccount was deleted, the cleanup runs once per namespace
+	legacySyncerServiceAccountsDeleted sync.Map
+}
+
+// getHubClusters returns the hubs currently known by the reconciler.
+func (r *RegisteredClusterReconciler) getHubClusters() []helpers.HubInstance {
+	r.hubClustersMutex.RLock()
+	defer r.hubClustersMutex.RUnlock()
+	return append([]helpers.HubInstance(nil), r.HubClusters...)
+}
+
+// addHubCluster adds a hub created after the controller start and watches its ManagedClusters and ManifestWorks.
+func (r *RegisteredClusterReconciler) addHubCluster(hubCluster helpers.HubInstance) error {
+	if err := r.watchHubCluster(hubCluster); err != nil {
+		return err
+	}
+	r.hubClustersMutex.Lock()
+	defer r.hubClustersMutex.Unlock()
+	r.HubClusters = append(r.HubClusters, hubCluster)
+	return nil
+}
+
+// replaceHubCluster replaces a hub by a new instance of the same HubConfig, built with its new credentials, and
+// watches the ManagedClusters and ManifestWorks of the new instance. The cache of the replaced instance is stopped,
+// so its watches no longer produce events and its informers are released.
+func (r *RegisteredClusterReconciler) replaceHubCluster(hubCluster helpers.HubInstance) error {
+	if err := r.watchHubCluster(hubCluster); err != nil {
+		hubCluster.Stop()
+		return err
+	}
+	r.hubClustersMutex.Lock()
+	defer r.hubClustersMutex.Unlock()
+	for i := range r.HubClusters {
+		if r.HubClusters[i].HubConfig.Name == hubCluster.HubConfig.Name {
+			replacedHubCluster := r.HubClusters[i]
+			r.HubClusters[i] = hubCluster
+			replacedHubCluster.Stop()
+			return nil
+		}
+	}
+	r.HubClusters = append(r.HubClusters, hubCluster)
+	return nil
+}
+
+// requeueHubRegisteredClusters enqueues the RegisteredClusters of all workspaces of the given hub, so their
+// HubReachable condition reflects a health change of the hub without waiting for an event.
+func (r *RegisteredClusterReconciler) requeueHubRegisteredClusters(computeCtx context.Context, hubName string) error {
+	// The context has no workspace, the cached list returns the RegisteredClusters of all workspaces
+	regClusters := &singaporev1alpha1.RegisteredClusterList{}
+	if err := r.Client.List(computeCtx, regClusters); err != nil {
+		return giterrors.WithStack(err)
+	}
+	hubClusters := r.getHubClusters()
+	for i := range regClusters.Items {
+		regCluster := &regClusters.Items[i]
+		hubCluster, err := helpers.GetHubCluster(regCluster.Namespace, regCluster.GetAnnotations(), hubClusters)
+		if err != nil || hubCluster.HubConfig.Name != hubName {
+			continue
+		}
+		select {
+		case r.hubEvents <- event.GenericEvent{Object: regCluster}:
+		case <-computeCtx.Done():
+			return computeCtx.Err()
+		}
+	}
+	return nil
+}
+
+// syncHubReachableCondition validates the hub of the RegisteredCluster before it is used by the reconcile. The
+// HubReachable condition is set to false when the hub is not usable and back to true once the hub is usable.
+func (r *RegisteredClusterReconciler) syncHubReachableCondition(computeCtx context.Context, hubCtx context.Context,
+	regCluster *singaporev1alpha1.RegisteredCluster,
+	hubCluster *helpers.HubInstance) error {
+	status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, singaporev1alpha1.RegisteredClusterConditionHubReachable)
+	validateErr := hubCluster.Validate(hubCtx)
+	if validateErr == nil {
+		if !ok || status == metav1.ConditionTrue {
+			return nil
+		}
+		return r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+			Type:    singaporev1alpha1.RegisteredClusterConditionHubReachable,
+			Status:  metav1.ConditionTrue,
+			Reason:  "HubReachable",
+			Message: fmt.Sprintf("The hub %s is reachable", hubCluster.HubConfig.Name),
+		})
+	}
+	if !ok || status != metav1.ConditionFalse {
+		r.Recorder.Event(regCluster, corev1.EventTypeWarning, "HubUnreachable", validateErr.Error())
+	}
+	if err := r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionHubReachable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "HubUnreachable",
+		Message: validateErr.Error(),
+	}); err != nil {
+		return err
+	}
+	return validateErr
+}
+
+// syncThrottledCondition sets the Throttled condition when the reconcile failed with a too many requests error,
+// which means an apiserver, usually the hub, applies backpressure, and requeues the RegisteredCluster after the
+// suggested delay. The condition is reset once a reconcile succeeds.
+func (r *RegisteredClusterReconciler) syncThrottledCondition(computeCtx context.Context,
+	regCluster *singaporev1alpha1.RegisteredCluster,
+	hubCluster *helpers.HubInstance,
+	result ctrl.Result,
+	reconcileErr error) (ctrl.Result, error) {
+	condition := metav1.Condition{
+		Type: singaporev1alpha1.RegisteredClusterConditionThrottled,
+	}
+	switch {
+	case k8serrors.IsTooManyRequests(reconcileErr):
+		r.Log.Info("reconcile throttled by apiserver backpressure",
+			"namespace", regCluster.Namespace,
+			"name", regCluster.Name,
+			"hub", hubCluster.HubConfig.Name,
+			"error", reconcileErr.Error())
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "TooManyRequests"
+		condition.Message = fmt.Sprintf("The reconcile is throttled by the apiserver backpressure, hub %s: %s",
+			hubCluster.HubConfig.Name, reconcileErr.Error())
+		delay, ok := k8serrors.SuggestsClientDelay(reconcileErr)
+		if !ok {
+			delay = 5
+		}
+		result = r.Backoff.Requeue(requeueKey(regCluster), time.Duration(delay)*time.Second)
+		reconcileErr = nil
+	case reconcileErr == nil:
+		if status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, condition.Type); !ok || status != metav1.ConditionTrue {
+			return result, nil
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NotThrottled"
+		condition.Message = "The reconcile is not throttled"
+	default:
+		return result, reconcileErr
+	}
+	if err := r.patchStatusConditions(computeCtx, regCluster, condition); err != nil && !k8serrors.IsNotFound(err) {
+		return result, err
+	}
+	return result, reconcileErr
+}
+
+// newStatusWriteContext returns the context of the status writes deferred to the end of the reconcile. It holds the
+// logical cluster and the status batch of computeCtx but not its deadline, so the status of a reconcile which timed
+// out is still written, within the short statusWriteTimeout.
+func newStatusWriteContext(computeCtx context.Context) (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	if cluster, ok := logicalcluster.ClusterFromContext(computeCtx); ok {
+		ctx = logicalcluster.WithCluster(ctx, cluster)
+	}
+	if batch := statusBatchFrom(computeCtx); batch != nil {
+		ctx = context.WithValue(ctx, statusBatchKey{}, batch)
+	}
+	return context.WithTimeout(ctx, statusWriteTimeout)
+}
+
+// checkRegisteredClusterAPI returns an actionable error if the RegisteredCluster API is not served in the
+// workspace, which happens when the workspace has no APIBinding to the compute APIExport.
+func (r *RegisteredClusterReconciler) checkRegisteredClusterAPI(computeCtx context.Context, clusterName string) error {
+	served, err := helpers.IsResourceServed(computeCtx, r.ComputeKubeClient.Discovery(), registeredClusterGVR)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	if !served {
+		return fmt.Errorf("the %s resource is not served in workspace %s, check the workspace has an APIBinding to the compute-apis APIExport",
+			registeredClusterGVR.GroupResource(), clusterName)
+	}
+	return nil
 }
 
-func (r *RegisteredClusterReconciler) Reconcile(computeContextOri context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = context.Background()
-	ctx := context.TODO()
+func (r *RegisteredClusterReconciler) getManifestWorkNamespace(managedCluster *clusterapiv1.ManagedCluster) string {
+	if r.ManifestWorkNamespaceFunc != nil {
+		return r.ManifestWorkNamespaceFunc(managedCluster)
+	}
+	return managedCluster.Name
+}
+
+// Reconcile syncs a RegisteredCluster of the compute service with its ManagedCluster on the hub.
+//
+// The reconcile works with two contexts, the methods take them in this order and name them consistently:
+//   - computeCtx is the context of the compute service calls, it holds the logical cluster of the
+//     RegisteredCluster workspace, and the status batch when enabled. It is used with r.Client and the
+//     Compute* clients. A call to a location workspace uses a locationContext, a copy of computeCtx holding
+//     the location logical cluster instead.
+//   - hubCtx is the context of the hub calls, made with the HubInstance clients and r.KubeClient. It holds no
+//     logical cluster, the hubs are not kcp workspaces.
+//
+// Both contexts expire with the reconcile timeout. A method which only calls the hub takes hubCtx alone, a
+// method which only calls the compute service takes computeCtx alone.
+func (r *RegisteredClusterReconciler) Reconcile(computeCtxOri context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	// A hung compute or hub API call must not block the worker, all the calls of the reconcile share its timeout
+	reconcileTimeout := r.getReconcileTimeout()
+	hubCtx, cancelHub := context.WithTimeout(context.Background(), reconcileTimeout)
+	defer cancelHub()
 	// Return a copy of the conext and injects the cluster name in the copied context
-	computeContext := logicalcluster.WithCluster(computeContextOri, logicalcluster.New(req.ClusterName))
+	computeCtx, cancelCompute := context.WithTimeout(logicalcluster.WithCluster(computeCtxOri, logicalcluster.New(req.ClusterName)), reconcileTimeout)
+	defer cancelCompute()
 	logger := r.Log.WithValues("clusterName", req.ClusterName, "namespace", req.Namespace, "name", req.Name)
 	logger.V(1).Info("Reconciling....")
 
 	regCluster := &singaporev1alpha1.RegisteredCluster{}
 
 	if err := r.Client.Get(
-		computeContext,
+		computeCtx,
 		types.NamespacedName{Namespace: req.Namespace, Name: req.Name},
 		regCluster); err != nil {
+		// A missing resource, and not a missing RegisteredCluster, means the workspace doesn't serve the API
+		if helpers.IsResourceNotFound(err) {
+			if apiErr := r.checkRegisteredClusterAPI(computeCtx, req.ClusterName); apiErr != nil {
+				logger.Error(apiErr, "RegisteredCluster API not available in the workspace")
+				return reconcile.Result{}, apiErr
+			}
+		}
 		if k8serrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
@@ -117,87 +447,284 @@ func (r *RegisteredClusterReconciler) Reconcile(computeContextOri context.Contex
 		return reconcile.Result{}, giterrors.WithStack(err)
 	}
 
-	hubCluster, err := helpers.GetHubCluster(req.Namespace, r.HubClusters)
+	hubCluster, err := helpers.GetHubCluster(req.Namespace, regCluster.GetAnnotations(), r.getHubClusters())
 	if err != nil {
 		logger.Error(err, "failed to get HubCluster for RegisteredCluster workspace")
+		r.Recorder.Event(regCluster, corev1.EventTypeWarning, "HubClusterNotFound",
+			fmt.Sprintf("Failed to get the hub cluster of the RegisteredCluster: %s", err.Error()))
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncHubReachableCondition(computeCtx, hubCtx, regCluster, &hubCluster); err != nil {
+		logger.Error(err, "HubCluster of the RegisteredCluster is not usable")
 		return ctrl.Result{}, err
 	}
 
+	// Write all the status changes of the reconcile with a single patch, after the Throttled condition
+	if r.BatchStatusUpdates {
+		var batch *statusBatch
+		computeCtx, batch = withStatusBatch(computeCtx)
+		defer func() {
+			statusCtx, cancelStatus := newStatusWriteContext(computeCtx)
+			defer cancelStatus()
+			if flushErr := r.flushStatus(statusCtx, regCluster, batch); flushErr != nil {
+				logger.Error(flushErr, "failed to flush the registered cluster status")
+				if err == nil {
+					err = flushErr
+				}
+			}
+		}()
+	}
+
+	// Reflect the apiserver backpressure in the Throttled condition
+	defer func() {
+		statusCtx, cancelStatus := newStatusWriteContext(computeCtx)
+		defer cancelStatus()
+		result, err = r.syncThrottledCondition(statusCtx, regCluster, &hubCluster, result, err)
+	}()
+
 	controllerutil.AddFinalizer(regCluster, helpers.RegisteredClusterFinalizer)
 
 	logger.V(2).Info("Add finalizer")
-	if err := r.Client.Update(computeContext, regCluster); err != nil {
+	if err := r.Client.Update(computeCtx, regCluster); err != nil {
 		return ctrl.Result{}, giterrors.WithStack(err)
 	}
 
+	// Acknowledge a new RegisteredCluster before it is provisioned
+	if r.ProvisionedCondition && regCluster.DeletionTimestamp == nil {
+		if _, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, singaporev1alpha1.RegisteredClusterConditionProvisioned); !ok {
+			if err := r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+				Type:    singaporev1alpha1.RegisteredClusterConditionProvisioned,
+				Status:  metav1.ConditionFalse,
+				Reason:  "Pending",
+				Message: "The RegisteredCluster is being provisioned",
+			}); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
 	// TODO create managedclusterset for workspace
 
+	// Check the location workspaces before anything is created for them
+	if regCluster.DeletionTimestamp == nil {
+		valid, err := r.syncInvalidLocationCondition(computeCtx, regCluster)
+		if err != nil {
+			logger.Error(err, "failed to validate the locations")
+			return ctrl.Result{}, err
+		}
+		if !valid {
+			return r.Backoff.Requeue(requeueKey(regCluster), invalidLocationRequeuePeriod), nil
+		}
+	}
+
 	if regCluster.DeletionTimestamp == nil {
 		// create managecluster on creation of registeredcluster CR
-		if err := r.createManagedCluster(ctx, regCluster, &hubCluster, req.ClusterName); err != nil {
+		if err := r.createManagedCluster(computeCtx, hubCtx, regCluster, &hubCluster, req.ClusterName); err != nil {
 			logger.Error(err, "failed to create ManagedCluster")
 			return ctrl.Result{}, err
 		}
 	}
-	managedCluster, err := r.getManagedCluster(ctx, regCluster, &hubCluster, req.ClusterName)
+	managedCluster, err := r.getManagedCluster(hubCtx, regCluster, &hubCluster, req.ClusterName)
 	if err != nil && !k8serrors.IsNotFound(err) {
 		logger.Error(err, "failed to get ManagedCluster")
+		r.Recorder.Event(regCluster, corev1.EventTypeWarning, "ManagedClusterNotFound",
+			fmt.Sprintf("Failed to get the ManagedCluster of the RegisteredCluster: %s", err.Error()))
 		return ctrl.Result{}, err
 	}
 
+	// The ManagedCluster workspace annotation must match the reconciled workspace, it is given to the kcp-syncer
+	if regCluster.DeletionTimestamp == nil {
+		if err := r.syncManagedClusterWorkspace(hubCtx, &managedCluster, &hubCluster, req.ClusterName); err != nil {
+			logger.Error(err, "failed to sync the ManagedCluster workspace annotation")
+			return ctrl.Result{}, err
+		}
+	}
+
 	//if deletetimestamp then process deletion
 	if regCluster.DeletionTimestamp != nil {
-		if r, err := r.processRegclusterDeletion(ctx, regCluster, &managedCluster, &hubCluster); err != nil || r.Requeue {
+		if r, err := r.processRegclusterDeletion(computeCtx, hubCtx, regCluster, &managedCluster, &hubCluster); err != nil || r.Requeue || r.RequeueAfter > 0 {
 			return r, err
 		}
+		// The cache may lag, keep the finalizer until the hub confirms the teardown
+		deleted, err := r.isRegclusterTeardownComplete(hubCtx, regCluster, &managedCluster, &hubCluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !deleted {
+			logger.Info("waiting the kcp-syncer manifestworks and the managedcluster to be deleted")
+			return r.Backoff.Requeue(requeueKey(regCluster), r.getClusterDeletionRequeue()), nil
+		}
 		controllerutil.RemoveFinalizer(regCluster, helpers.RegisteredClusterFinalizer)
-		if err := r.Client.Update(computeContext, regCluster); err != nil {
+		if err := r.Client.Update(computeCtx, regCluster); err != nil {
 			return ctrl.Result{}, giterrors.WithStack(err)
 		}
+		r.Backoff.Forget(requeueKey(regCluster))
 		return reconcile.Result{}, nil
 	}
 
-	// update status of registeredcluster - add import command
-	// TODO - skip creating the secret if cluster is already imported - and maybe delete it once cluster is imported?
-	if err := r.updateImportCommand(computeContext, ctx, regCluster, &managedCluster, &hubCluster); err != nil {
+	// the force-resync annotation re-applies all downstream resources once
+	_, forceResync := regCluster.GetAnnotations()[ForceResyncAnnotation]
+	if forceResync {
+		logger.Info("force re-sync of all downstream resources")
+	}
+
+	// update status of registeredcluster - add import command until the cluster joins, then remove it
+	if status, ok := helpers.GetConditionStatus(managedCluster.Status.Conditions, clusterapiv1.ManagedClusterConditionJoined); ok && status == metav1.ConditionTrue {
+		if err := r.removeImportCommand(computeCtx, regCluster); err != nil {
+			logger.Error(err, "failed to remove import command")
+			return ctrl.Result{}, err
+		}
+	} else if err := r.updateImportCommand(computeCtx, hubCtx, regCluster, &managedCluster, &hubCluster, forceResync); err != nil {
 		if k8serrors.IsNotFound(err) {
-			return reconcile.Result{Requeue: true, RequeueAfter: 1 * time.Second}, nil
+			if err := r.syncImportSecretGeneratedCondition(computeCtx, regCluster, &managedCluster, false); err != nil {
+				return ctrl.Result{}, err
+			}
+			// The requeue delay grows exponentially while the import secret is not generated
+			return r.Backoff.Requeue(requeueKey(regCluster), 1*time.Second), nil
 		}
 		logger.Error(err, "failed to update import command")
 		return ctrl.Result{}, err
+	} else if err := r.syncImportSecretGeneratedCondition(computeCtx, regCluster, &managedCluster, true); err != nil {
+		return ctrl.Result{}, err
 	}
 	// update status of registeredcluster
-	if err := r.updateRegisteredClusterStatus(computeContext, regCluster, &managedCluster); err != nil {
+	if err := r.updateRegisteredClusterStatus(computeCtx, regCluster, &managedCluster); err != nil {
 		logger.Error(err, "failed to update registered cluster status")
 		return ctrl.Result{}, err
 	}
+	if r.PropagateConsoleURL {
+		if err := r.syncConsoleURL(computeCtx, hubCtx, regCluster, &managedCluster, &hubCluster); err != nil {
+			logger.Error(err, "failed to sync console url")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// remove the kcp-syncer from a ManagedCluster having an eviction taint
+	evicted, err := r.syncSyncerEviction(computeCtx, hubCtx, regCluster, &managedCluster, &hubCluster)
+	if err != nil {
+		logger.Error(err, "failed to sync kcp-syncer eviction")
+		return ctrl.Result{}, err
+	}
 
-	if len(regCluster.Spec.Location) > 0 {
+	// reflect the fully qualified location workspace paths for tooling, the locations are still used as set in the spec
+	locationPaths := getLocationPaths(regCluster)
+	if !equality.Semantic.DeepEqual(regCluster.Status.LocationPath, locationPaths) {
+		if err := r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+			"locationPath": locationPaths,
+		}); err != nil {
+			logger.Error(err, "failed to update the location path")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// A RegisteredCluster not deploying the kcp-syncer is only registered for inventory and status
+	if !helpers.IsSyncerDeployed(regCluster) && regCluster.Status.SyncerLastAppliedTime != nil {
+		removing, err := r.removeKcpSyncer(computeCtx, hubCtx, regCluster, &managedCluster, &hubCluster)
+		if err != nil {
+			logger.Error(err, "failed to remove the kcp-syncer")
+			return ctrl.Result{}, err
+		}
+		if removing {
+			return r.Backoff.Requeue(requeueKey(regCluster), r.getWorkDeletionRequeue()), nil
+		}
+	}
+
+	if len(regCluster.Spec.Location) > 0 && !evicted && helpers.IsSyncerDeployed(regCluster) {
 		for _, locationWorkspace := range regCluster.Spec.Location {
 			// sync SyncTarget
-			if err := r.syncSyncTarget(computeContext, regCluster, locationWorkspace, &managedCluster); err != nil {
+			if err := r.syncSyncTarget(computeCtx, regCluster, locationWorkspace, &managedCluster, forceResync); err != nil {
 				logger.Error(err, "failed to sync SyncTarget in location workspace %s", locationWorkspace)
 				return ctrl.Result{}, giterrors.WithStack(err)
 			}
 
 			// sync kcp-syncer service account (currently one per location workspace - probably change to one per syncer, owned by the syncer) in kcp workspace
-			token := ""
-			if token, err = r.syncServiceAccount(computeContext, ctx, regCluster, locationWorkspace, &managedCluster, &hubCluster); err != nil {
+			tokenSecret, err := r.syncServiceAccount(computeCtx, hubCtx, regCluster, locationWorkspace, &managedCluster, &hubCluster)
+			if err != nil {
 				logger.Error(err, "failed to sync ServiceAccount in the location workspace %s", locationWorkspace)
 				return ctrl.Result{}, err
 			}
+			if tokenSecret == nil {
+				// No SyncTarget yet, the ManagedCluster has not joined
+				continue
+			}
 
 			// sync kcp-syncer deployment and supporting resources
-			if err := r.syncKcpSyncer(computeContext, ctx, regCluster, locationWorkspace, &managedCluster, &hubCluster, token); err != nil {
+			if err := r.syncKcpSyncer(computeCtx, hubCtx, regCluster, locationWorkspace, &managedCluster, &hubCluster, tokenSecret, forceResync); err != nil {
 				logger.Error(err, "failed to sync kcp-syncer in the location workspace %s", locationWorkspace)
 				return ctrl.Result{}, err
 			}
+
+			if err := r.deleteLegacyKcpSyncerServiceAccount(computeCtx, regCluster, locationWorkspace); err != nil {
+				logger.Error(err, "failed to delete the legacy kcp-syncer service account in the location workspace %s", locationWorkspace)
+				return ctrl.Result{}, err
+			}
 		}
 	}
 
+	if forceResync {
+		patch := client.MergeFrom(regCluster.DeepCopy())
+		delete(regCluster.Annotations, ForceResyncAnnotation)
+		if err := r.Client.Patch(computeCtx, regCluster, patch); err != nil {
+			return ctrl.Result{}, giterrors.WithStack(err)
+		}
+		logger.Info("force re-sync done")
+	}
+
+	// The status update is filtered by the registeredClusterPredicate and doesn't trigger a new reconcile
+	if regCluster.Status.ObservedGeneration != regCluster.Generation {
+		if err := r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+			"observedGeneration": regCluster.Generation,
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if r.ProvisionedCondition {
+		if err := r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+			Type:    singaporev1alpha1.RegisteredClusterConditionProvisioned,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Provisioned",
+			Message: "The RegisteredCluster is provisioned",
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.Backoff.Forget(requeueKey(regCluster))
+
+	// The hub import secret is not watched, check it periodically until the cluster joins
+	if status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, clusterapiv1.ManagedClusterConditionJoined); !ok || status != metav1.ConditionTrue {
+		return r.Backoff.Resync(importSecretResyncPeriod), nil
+	}
+
+	// The kcp-syncer token secrets are not watched, check them periodically for a rotation,
+	// often enough to renew a bound token before it expires
+	if len(locationPaths) > 0 && !evicted && helpers.IsSyncerDeployed(regCluster) {
+		resyncPeriod := syncerTokenResyncPeriod
+		if tokenResyncPeriod := r.getSyncerTokenExpiration() / 10; tokenResyncPeriod < resyncPeriod {
+			resyncPeriod = tokenResyncPeriod
+		}
+		return r.Backoff.Resync(resyncPeriod), nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// requeueKey identifies a RegisteredCluster in the requeue backoff
+func requeueKey(regCluster *singaporev1alpha1.RegisteredCluster) string {
+	return fmt.Sprintf("%s|%s/%s", logicalcluster.From(regCluster), regCluster.Namespace, regCluster.Name)
+}
+
+// getLocationPaths returns the fully qualified workspace paths of the RegisteredCluster locations
+func getLocationPaths(regCluster *singaporev1alpha1.RegisteredCluster) []string {
+	var locationPaths []string
+	for _, location := range regCluster.Spec.Location {
+		locationPaths = append(locationPaths, helpers.GetLocationPath(logicalcluster.From(regCluster).String(), location))
+	}
+	return locationPaths
+}
+
 // List of regexes to exclude from labels and cluster claims copied to sync target labels
 var excludeLabelREs = []string{
 	"^feature\\.open-cluster-management\\.io\\/addon",
@@ -267,13 +794,13 @@ func (r *RegisteredClusterReconciler) getSyncTarget(locationContext context.Cont
 
 }
 
-func (r *RegisteredClusterReconciler) syncSyncTarget(computeContext context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, managedCluster *clusterapiv1.ManagedCluster) error {
+func (r *RegisteredClusterReconciler) syncSyncTarget(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, managedCluster *clusterapiv1.ManagedCluster, forceResync bool) error {
 
 	logger := r.Log.WithName("syncSyncTarget").WithValues("namespace", regCluster.Namespace, "name", regCluster.Name, "managed cluster name", managedCluster.Name, "Location workspace", locationWorkspace)
 
 	if status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, clusterapiv1.ManagedClusterConditionJoined); ok && status == metav1.ConditionTrue {
 
-		locationContext := logicalcluster.WithCluster(computeContext, logicalcluster.New(locationWorkspace))
+		locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
 
 		syncTarget, err := r.getSyncTarget(locationContext, regCluster)
 		if err != nil {
@@ -321,7 +848,7 @@ func (r *RegisteredClusterReconciler) syncSyncTarget(computeContext context.Cont
 			syncTargetLabels := syncTarget.GetLabels()
 			modified := mergeMap(&syncTargetLabels, labels)
 
-			if modified {
+			if modified || forceResync {
 				syncTarget.SetLabels(syncTargetLabels)
 				if _, err := r.ComputeDynamicClient.Resource(syncTargetGVR).Update(locationContext, syncTarget, metav1.UpdateOptions{}); err != nil {
 					return err
@@ -363,46 +890,191 @@ func mergeMap(existing *map[string]string, required map[string]string) bool {
 	return modified
 }
 
-func (r *RegisteredClusterReconciler) updateRegisteredClusterStatus(computeContext context.Context, regCluster *singaporev1alpha1.RegisteredCluster, managedCluster *clusterapiv1.ManagedCluster) error {
+// patchStatus patches only the given RegisteredCluster status fields with a merge patch,
+// so the status fields owned by the other reconcile phases are never reverted.
+// The fields are only accumulated if the context carries a status batch.
+func (r *RegisteredClusterReconciler) patchStatus(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, status map[string]interface{}) error {
+	if batch := statusBatchFrom(computeCtx); batch != nil {
+		return batch.addFields(regCluster, status)
+	}
+	data, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	r.Log.V(2).Info("patch registeredcluster status",
+		"namespace", regCluster.Namespace,
+		"name", regCluster.Name,
+		"patch", string(data))
+	if err := r.Client.Status().Patch(computeCtx, regCluster, client.RawPatch(types.MergePatchType, data)); err != nil {
+		return giterrors.WithStack(err)
+	}
+	return nil
+}
+
+// patchStatusConditions merges the given conditions in the RegisteredCluster status. A merge patch replaces the whole
+// conditions list, the patch is done with an optimistic lock to not revert a condition written concurrently.
+// The conditions are only accumulated if the context carries a status batch.
+func (r *RegisteredClusterReconciler) patchStatusConditions(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, conditions ...metav1.Condition) error {
+	if batch := statusBatchFrom(computeCtx); batch != nil {
+		regCluster.Status.Conditions = helpers.MergeStatusConditions(regCluster.Status.Conditions, conditions...)
+		batch.conditions = append(batch.conditions, conditions...)
+		return nil
+	}
+	patch := client.MergeFromWithOptions(regCluster.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	regCluster.Status.Conditions = helpers.MergeStatusConditions(regCluster.Status.Conditions, conditions...)
+	if err := r.Client.Status().Patch(computeCtx, regCluster, patch); err != nil {
+		return giterrors.WithStack(err)
+	}
+	return nil
+}
+
+// resourceListPatch returns the merge patch of a resource list, the resources not in the desired list are removed.
+func resourceListPatch(existing, desired clusterapiv1.ResourceList) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for name := range existing {
+		patch[string(name)] = nil
+	}
+	for name, quantity := range desired {
+		patch[string(name)] = quantity
+	}
+	return patch
+}
+
+func (r *RegisteredClusterReconciler) updateRegisteredClusterStatus(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, managedCluster *clusterapiv1.ManagedCluster) error {
 	r.Log.V(2).Info("updateRegisteredClusterStatus",
 		"regcluster", regCluster.Name,
 		"managedCluster", managedCluster.Name)
-	patch := client.MergeFrom(regCluster.DeepCopy())
 	if managedCluster.Status.Conditions != nil {
-		regCluster.Status.Conditions = helpers.MergeStatusConditions(regCluster.Status.Conditions, managedCluster.Status.Conditions...)
+		if err := r.patchStatusConditions(computeCtx, regCluster, managedCluster.Status.Conditions...); err != nil {
+			return err
+		}
+	}
+
+	// The Ready condition aggregates the import, join and kcp-syncer phases
+	readyCondition := helpers.GetReadyCondition(regCluster)
+	if existing := meta.FindStatusCondition(regCluster.Status.Conditions, readyCondition.Type); existing == nil ||
+		existing.Status != readyCondition.Status || existing.Reason != readyCondition.Reason || existing.Message != readyCondition.Message {
+		if err := r.patchStatusConditions(computeCtx, regCluster, readyCondition); err != nil {
+			return err
+		}
 	}
+
+	// The status fields owned by this phase and copied from the managedcluster
+	status := map[string]interface{}{}
 	if managedCluster.Status.Allocatable != nil {
-		regCluster.Status.Allocatable = managedCluster.Status.Allocatable
+		status["allocatable"] = resourceListPatch(regCluster.Status.Allocatable, managedCluster.Status.Allocatable)
 	}
 	if managedCluster.Status.Capacity != nil {
-		regCluster.Status.Capacity = managedCluster.Status.Capacity
+		status["capacity"] = resourceListPatch(regCluster.Status.Capacity, managedCluster.Status.Capacity)
 	}
-	if managedCluster.Status.ClusterClaims != nil {
-		regCluster.Status.ClusterClaims = managedCluster.Status.ClusterClaims
+	if managedCluster.Status.ClusterClaims != nil || regCluster.Spec.InitialClusterClaims != nil {
+		status["clusterClaims"] = helpers.MergeClusterClaims(managedCluster.Status.ClusterClaims, regCluster.Spec.InitialClusterClaims)
 	}
 	if managedCluster.Status.Version != (clusterapiv1.ManagedClusterVersion{}) {
-		regCluster.Status.Version = managedCluster.Status.Version
+		status["version"] = managedCluster.Status.Version
+	} else if regCluster.Status.Version != (clusterapiv1.ManagedClusterVersion{}) {
+		// the version is unknown, a null removes the stale version
+		status["version"] = nil
 	}
 	if managedCluster.Spec.ManagedClusterClientConfigs != nil && len(managedCluster.Spec.ManagedClusterClientConfigs) > 0 {
-		regCluster.Status.ApiURL = managedCluster.Spec.ManagedClusterClientConfigs[0].URL
+		status["apiURL"] = managedCluster.Spec.ManagedClusterClientConfigs[0].URL
 	}
+	// some hubs only expose the cluster ID as a cluster claim
 	if clusterID, ok := managedCluster.GetLabels()["clusterID"]; ok {
-		regCluster.Status.ClusterID = clusterID
+		status["clusterID"] = clusterID
+	} else if clusterID, ok := helpers.GetClusterClaimValue(managedCluster.Status.ClusterClaims, helpers.ClusterIDClaimName); ok {
+		status["clusterID"] = clusterID
 	}
-	r.Log.V(2).Info("updateRegisteredClusterStatus",
-		"patch", patch,
-		"regcluster", regCluster.Status)
-	if err := r.Client.Status().Patch(computeContext, regCluster, patch); err != nil {
+	if hostingCluster := managedCluster.GetAnnotations()[HostingClusterAnnotation]; len(hostingCluster) != 0 {
+		status["hostingCluster"] = hostingCluster
+	} else if len(regCluster.Status.HostingCluster) != 0 {
+		// the klusterlet is no longer hosted, a null removes the field
+		status["hostingCluster"] = nil
+	}
+	if len(status) == 0 {
+		return nil
+	}
+	return r.patchStatus(computeCtx, regCluster, status)
+}
+
+// syncConsoleURL copies the console URL reported by the hub ManagedClusterInfo in the RegisteredCluster status.
+// The ManagedClusterInfo is not watched, it is read with the uncached reader as it only exists on hubs
+// running the multicluster foundation, the status is refreshed on the next reconcile.
+func (r *RegisteredClusterReconciler) syncConsoleURL(computeCtx context.Context, hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance) error {
+	clusterInfo := &unstructured.Unstructured{}
+	clusterInfo.SetGroupVersionKind(managedClusterInfoGVK)
+	err := hubCluster.Cluster.GetAPIReader().Get(hubCtx, types.NamespacedName{Name: managedCluster.Name, Namespace: managedCluster.Name}, clusterInfo)
+	switch {
+	case k8serrors.IsNotFound(err), meta.IsNoMatchError(err):
+		r.Log.V(2).Info("managedclusterinfo not found", "managedcluster", managedCluster.Name, "hub", hubCluster.HubConfig.Name)
+		return nil
+	case err != nil:
+		return giterrors.WithStack(err)
+	}
+
+	consoleURL, _, err := unstructured.NestedString(clusterInfo.Object, "status", "consoleURL")
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	if consoleURL == regCluster.Status.ConsoleURL {
+		return nil
+	}
+	return r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+		"consoleURL": consoleURL,
+	})
+}
+
+// syncManagedClusterWorkspace repairs the workspace annotation of the ManagedCluster when it doesn't match the
+// workspace of the RegisteredCluster, for example after a workspace move. The ManagedCluster is selected with the
+// labels of the workspace, so the annotation is the stale value.
+func (r *RegisteredClusterReconciler) syncManagedClusterWorkspace(hubCtx context.Context, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance, clusterName string) error {
+	if len(managedCluster.Name) == 0 {
+		return nil
+	}
+	annotatedClusterName := managedCluster.GetAnnotations()[ClusterNameAnnotation]
+	if annotatedClusterName == clusterName {
+		return nil
+	}
+	r.Log.Info("managedcluster workspace annotation doesn't match the registeredcluster workspace, updating it",
+		"managed cluster name", managedCluster.Name,
+		"annotation", annotatedClusterName,
+		"workspace", clusterName)
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	if managedCluster.Annotations == nil {
+		managedCluster.Annotations = map[string]string{}
+	}
+	managedCluster.Annotations[ClusterNameAnnotation] = clusterName
+	if err := hubCluster.Client.Patch(hubCtx, managedCluster, patch); err != nil {
 		return giterrors.WithStack(err)
 	}
+	return nil
+}
 
+// deleteDuplicateManagedClusters deletes the duplicate ManagedClusters of a RegisteredCluster. A duplicate which
+// joined is kept as a cluster may have been imported with it.
+func (r *RegisteredClusterReconciler) deleteDuplicateManagedClusters(hubCtx context.Context, hubCluster *helpers.HubInstance, duplicates []clusterapiv1.ManagedCluster) error {
+	for i := range duplicates {
+		duplicate := &duplicates[i]
+		if status, ok := helpers.GetConditionStatus(duplicate.Status.Conditions, clusterapiv1.ManagedClusterConditionJoined); ok && status == metav1.ConditionTrue {
+			r.Log.Info("duplicate managedcluster joined, not deleting it", "name", duplicate.Name)
+			continue
+		}
+		r.Log.Info("delete duplicate managedcluster", "name", duplicate.Name)
+		if err := hubCluster.Client.Delete(hubCtx, duplicate); err != nil && !k8serrors.IsNotFound(err) {
+			return giterrors.WithStack(err)
+		}
+	}
 	return nil
 }
 
-func (r *RegisteredClusterReconciler) getManagedCluster(ctx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, hubCluster *helpers.HubInstance, clusterName string) (clusterapiv1.ManagedCluster, error) {
+func (r *RegisteredClusterReconciler) getManagedCluster(hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, hubCluster *helpers.HubInstance, clusterName string) (clusterapiv1.ManagedCluster, error) {
 	managedClusterList := &clusterapiv1.ManagedClusterList{}
 	managedCluster := clusterapiv1.ManagedCluster{}
-	if err := hubCluster.Client.List(ctx, managedClusterList, client.MatchingLabels(getRegisteredClusterLabels(regCluster, clusterName))); err != nil {
+	selector, err := getRegisteredClusterSelector(regCluster, clusterName)
+	if err != nil {
+		return managedCluster, err
+	}
+	if err := hubCluster.Client.List(hubCtx, managedClusterList, selector); err != nil {
 		// Error reading the object - requeue the request.
 		return managedCluster, giterrors.WithStack(err)
 	}
@@ -412,8 +1084,20 @@ func (r *RegisteredClusterReconciler) getManagedCluster(ctx context.Context, reg
 		RegisteredClusterNamelabel, regCluster.Name,
 		RegisteredClusterNamespacelabel, regCluster.Namespace,
 		ManagedClusterSetlabel, helpers.ManagedClusterSetNameForWorkspace(clusterName))
-	if len(managedClusterList.Items) == 1 {
-		return managedClusterList.Items[0], nil
+	if selected, duplicates, ok := helpers.SelectManagedCluster(managedClusterList.Items); ok {
+		if len(duplicates) != 0 {
+			r.Log.Info("WARNING: more than one managedcluster found for the registeredcluster, using the oldest one",
+				"namespace", regCluster.Namespace,
+				"name", regCluster.Name,
+				"managed cluster name", selected.Name,
+				"duplicates", len(duplicates))
+			if r.DeleteDuplicateClusters && regCluster.DeletionTimestamp == nil {
+				if err := r.deleteDuplicateManagedClusters(hubCtx, hubCluster, duplicates); err != nil {
+					return selected, err
+				}
+			}
+		}
+		return selected, nil
 	}
 
 	if regCluster.DeletionTimestamp != nil {
@@ -422,16 +1106,17 @@ func (r *RegisteredClusterReconciler) getManagedCluster(ctx context.Context, reg
 	return managedCluster, fmt.Errorf("correct managedcluster not found")
 }
 
-func (r *RegisteredClusterReconciler) updateImportCommand(computeContext context.Context,
-	ctx context.Context,
+func (r *RegisteredClusterReconciler) updateImportCommand(computeCtx context.Context,
+	hubCtx context.Context,
 	regCluster *singaporev1alpha1.RegisteredCluster,
 	managedCluster *clusterapiv1.ManagedCluster,
-	hubCluster *helpers.HubInstance) error {
+	hubCluster *helpers.HubInstance,
+	forceResync bool) error {
 	r.Log.V(2).Info("updateImportCommand",
 		"registered cluster", regCluster.Name)
 	// get import secret from mce managecluster namespace
 	importSecret := &corev1.Secret{}
-	if err := hubCluster.Cluster.GetAPIReader().Get(ctx,
+	if err := hubCluster.Cluster.GetAPIReader().Get(hubCtx,
 		types.NamespacedName{Namespace: managedCluster.Name, Name: managedCluster.Name + "-import"},
 		importSecret); err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -440,13 +1125,53 @@ func (r *RegisteredClusterReconciler) updateImportCommand(computeContext context
 		return giterrors.WithStack(err)
 	}
 
-	applier := apply.NewApplierBuilder().
+	// The keys of the import secret depend on the MCE version of the hub
+	crds, importData, err := helpers.GetImportSecretData(importSecret)
+	if err != nil {
+		r.Recorder.Event(regCluster, corev1.EventTypeWarning, "ImportCommandFailed", err.Error())
+		return giterrors.WithStack(err)
+	}
+
+	importSecretRef := r.getImportSecretRef(regCluster)
+
+	// Detect if the hub CA was rotated since the import secret was generated
+	hubCAHash := helpers.GetImportHubCAHash(importData)
+	existingImportSecret, err := r.ComputeKubeClient.CoreV1().Secrets(importSecretRef.Namespace).Get(computeCtx, importSecretRef.Name, metav1.GetOptions{})
+	importSecretCreated := k8serrors.IsNotFound(err)
+	switch {
+	case err == nil:
+		previousHubCAHash := existingImportSecret.GetAnnotations()[HubCAHashAnnotation]
+		if len(previousHubCAHash) != 0 && len(hubCAHash) != 0 && previousHubCAHash != hubCAHash {
+			r.Log.Info("hub CA changed, regenerating the import secret",
+				"namespace", importSecretRef.Namespace,
+				"name", importSecretRef.Name)
+			r.Recorder.Event(regCluster, corev1.EventTypeNormal, "ImportSecretRegenerated",
+				"The hub CA changed, the import command is regenerated")
+		}
+		// Recreate the import secret as the applier skips an unchanged secret
+		if forceResync {
+			r.Log.Info("force re-sync, deleting the import secret",
+				"namespace", importSecretRef.Namespace,
+				"name", importSecretRef.Name)
+			err := r.ComputeKubeClient.CoreV1().Secrets(importSecretRef.Namespace).Delete(computeCtx, importSecretRef.Name, metav1.DeleteOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return giterrors.WithStack(err)
+			}
+		}
+	case !k8serrors.IsNotFound(err):
+		return giterrors.WithStack(err)
+	}
+
+	applierBuilder := apply.NewApplierBuilder().
 		WithClient(r.ComputeKubeClient,
 			r.ComputeAPIExtensionClient,
 			r.ComputeDynamicClient).
-		WithOwner(regCluster, false, true, r.Scheme).
-		WithContext(computeContext).
-		Build()
+		WithContext(computeCtx)
+	// An owner reference can not cross namespaces
+	if importSecretRef.Namespace == regCluster.Namespace {
+		applierBuilder = applierBuilder.WithOwner(regCluster, false, true, r.Scheme)
+	}
+	applier := applierBuilder.Build()
 
 	readerDeploy := resources.GetScenarioResourcesReader()
 
@@ -454,163 +1179,330 @@ func (r *RegisteredClusterReconciler) updateImportCommand(computeContext context
 		"cluster-registration/import_secret.yaml",
 	}
 
-	// Get yaml representation of import command
-
-	crdsv1Yaml, err := yaml.Marshal(importSecret.Data["crdsv1.yaml"])
-	if err != nil {
-		return giterrors.WithStack(err)
+	importCommandTemplate := r.ImportCommandTemplate
+	if importCommandTemplate == nil {
+		importCommandTemplate = defaultImportCommandTemplate
 	}
-
-	importYaml, err := yaml.Marshal(importSecret.Data["import.yaml"])
+	importCommandCLI := r.ImportCommandCLI
+	if len(importCommandCLI) == 0 {
+		importCommandCLI = helpers.DefaultImportCommandCLI
+	}
+	importCommand, err := helpers.BuildImportCommand(importCommandTemplate, importCommandCLI,
+		crds, importData, r.ImportCommandSleep)
 	if err != nil {
-		return giterrors.WithStack(err)
+		return giterrors.WithStack(fmt.Errorf("import secret %s/%s: %w", importSecret.Namespace, importSecret.Name, err))
 	}
 
-	importCommand := "echo \"" + strings.TrimSpace(string(crdsv1Yaml)) + "\" | base64 --decode | kubectl apply -f - && sleep 2 && echo \"" + strings.TrimSpace(string(importYaml)) + "\" | base64 --decode | kubectl apply -f -"
-
 	values := struct {
-		Name          string
-		Namespace     string
-		ImportCommand string
-		ClusterName   string
+		Name                string
+		Namespace           string
+		ImportCommand       string
+		CRDs                string
+		Import              string
+		ClusterName         string
+		HubCAHashAnnotation string
+		HubCAHash           string
+		Labels              map[string]string
 	}{
-		Name:          regCluster.Name,
-		Namespace:     regCluster.Namespace,
-		ImportCommand: importCommand,
-		ClusterName:   logicalcluster.From(regCluster).String(),
+		Name:                importSecretRef.Name,
+		Namespace:           importSecretRef.Namespace,
+		ImportCommand:       importCommand,
+		CRDs:                helpers.EncodeImportData(crds),
+		Import:              helpers.EncodeImportData(importData),
+		ClusterName:         logicalcluster.From(regCluster).String(),
+		HubCAHashAnnotation: HubCAHashAnnotation,
+		HubCAHash:           hubCAHash,
+		Labels:              r.ImportSecretLabels,
 	}
 
 	r.Log.V(2).Info("create secret on compute",
 		"cluster", logicalcluster.From(regCluster).String(),
-		"namespace", regCluster.Namespace,
-		"name", regCluster.Name)
-
-	_, err = applier.ApplyDirectly(readerDeploy, values, false, "", files...)
+		"namespace", importSecretRef.Namespace,
+		"name", importSecretRef.Name)
+
+	// Retry only the apply on a transient error, so the reconcile progress is not reset by an apiserver hiccup
+	attempt := 0
+	err = retry.OnError(importApplyBackoff(r.ImportApplyRetries), helpers.IsTransientError, func() error {
+		attempt++
+		_, err := applier.ApplyDirectly(readerDeploy, values, false, "", files...)
+		if err != nil && attempt <= r.ImportApplyRetries && helpers.IsTransientError(err) {
+			r.Log.V(1).Info("transient error applying the import secret, retrying",
+				"namespace", importSecretRef.Namespace,
+				"name", importSecretRef.Name,
+				"attempt", attempt,
+				"error", err.Error())
+		}
+		return err
+	})
 	if err != nil {
+		r.Recorder.Event(regCluster, corev1.EventTypeWarning, "ImportCommandFailed",
+			fmt.Sprintf("Failed to create the import command secret %s/%s: %s", importSecretRef.Namespace, importSecretRef.Name, err.Error()))
 		return giterrors.WithStack(err)
 	}
+	if importSecretCreated {
+		r.Recorder.Event(regCluster, corev1.EventTypeNormal, "ImportCommandGenerated",
+			fmt.Sprintf("The import command is available in the secret %s/%s", importSecretRef.Namespace, importSecretRef.Name))
+	}
 
 	r.Log.V(2).Info("patch registeredCluster on compute with import secret",
 		"namespace", regCluster.Namespace,
 		"name", regCluster.Name)
-	patch := client.MergeFrom(regCluster.DeepCopy())
-	regCluster.Status.ImportCommandRef = corev1.LocalObjectReference{
-		Name: regCluster.Name + "-import",
-	}
-	if err := r.Client.Status().Patch(computeContext, regCluster, patch); err != nil {
-		return giterrors.WithStack(err)
+	// The import manifests are in the import command secret, they are exposed for the tools not running the command
+	if err := r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+		"importCommandRef":   importSecretRef,
+		"importManifestsRef": importSecretRef,
+	}); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-func (r *RegisteredClusterReconciler) syncServiceAccount(computeContext context.Context,
-	ctx context.Context,
+// hubDeletionBackoff is the backoff of the hub requests of the RegisteredCluster teardown, so a transient
+// hub error does not fail the reconcile. The retries are bounded by the reconcile context.
+var hubDeletionBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// importApplyBackoff returns the backoff of the import secret apply, retried the given number of times.
+func importApplyBackoff(retries int) wait.Backoff {
+	if retries < 0 {
+		retries = 0
+	}
+	return wait.Backoff{
+		Steps:    retries + 1,
+		Duration: 200 * time.Millisecond,
+		Factor:   2,
+		Jitter:   0.1,
+	}
+}
+
+// syncImportSecretGeneratedCondition sets the ImportSecretGenerated condition to false when the import secret
+// of the ManagedCluster is still not generated after the import secret timeout, and back to true once generated.
+// The condition is not set while the import secret is generated in time.
+func (r *RegisteredClusterReconciler) syncImportSecretGeneratedCondition(computeCtx context.Context,
 	regCluster *singaporev1alpha1.RegisteredCluster,
-	locationWorkspace string,
 	managedCluster *clusterapiv1.ManagedCluster,
-	hubCluster *helpers.HubInstance) (string, error) {
+	generated bool) error {
+	status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, singaporev1alpha1.RegisteredClusterConditionImportSecretGenerated)
+	if generated {
+		if !ok || status == metav1.ConditionTrue {
+			return nil
+		}
+		return r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+			Type:    singaporev1alpha1.RegisteredClusterConditionImportSecretGenerated,
+			Status:  metav1.ConditionTrue,
+			Reason:  "ImportSecretGenerated",
+			Message: fmt.Sprintf("The import secret %s/%s-import was generated", managedCluster.Name, managedCluster.Name),
+		})
+	}
+	if r.ImportSecretTimeout == 0 || managedCluster.CreationTimestamp.IsZero() ||
+		time.Since(managedCluster.CreationTimestamp.Time) < r.ImportSecretTimeout {
+		return nil
+	}
+	if ok && status == metav1.ConditionFalse {
+		return nil
+	}
+	message := fmt.Sprintf("The import secret %s/%s-import was not generated by the hub %s after the ManagedCluster creation",
+		managedCluster.Name, managedCluster.Name, r.ImportSecretTimeout)
+	r.Recorder.Event(regCluster, corev1.EventTypeWarning, "ImportSecretNotGenerated", message)
+	return r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionImportSecretGenerated,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ImportSecretNotGenerated",
+		Message: message,
+	})
+}
 
-	r.Log.V(2).Info("syncServiceAccount",
+// getImportSecretRef returns the reference of the compute secret holding the import command.
+// The name is prefixed with the RegisteredCluster namespace when the secret is created in a
+// dedicated namespace to avoid collisions between RegisteredClusters.
+func (r *RegisteredClusterReconciler) getImportSecretRef(regCluster *singaporev1alpha1.RegisteredCluster) corev1.SecretReference {
+	if len(r.ImportSecretNamespace) == 0 || r.ImportSecretNamespace == regCluster.Namespace {
+		return corev1.SecretReference{
+			Name:      regCluster.Name + "-import",
+			Namespace: regCluster.Namespace,
+		}
+	}
+	return corev1.SecretReference{
+		Name:      regCluster.Namespace + "-" + regCluster.Name + "-import",
+		Namespace: r.ImportSecretNamespace,
+	}
+}
+
+// removeImportCommand deletes the import secret of a joined cluster and clears the import command and manifests references.
+// The import secret is generated again if the cluster is detached.
+func (r *RegisteredClusterReconciler) removeImportCommand(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster) error {
+	importSecretRef := regCluster.Status.ImportCommandRef
+	if len(importSecretRef.Name) == 0 {
+		return nil
+	}
+	r.Log.Info("cluster joined, delete import secret", "namespace", importSecretRef.Namespace, "name", importSecretRef.Name)
+	err := r.ComputeKubeClient.CoreV1().Secrets(importSecretRef.Namespace).Delete(computeCtx, importSecretRef.Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+	return r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+		"importCommandRef":   nil,
+		"importManifestsRef": nil,
+	})
+}
+
+// deleteImportSecret deletes the import secret of a deleted RegisteredCluster, and the one of its status if the
+// import secret namespace changed since it was created. The owner reference garbage collection is not relied on
+// as it doesn't apply across workspaces. It returns true once the import secrets are gone.
+func (r *RegisteredClusterReconciler) deleteImportSecret(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster) (bool, error) {
+	importSecretRefs := []corev1.SecretReference{r.getImportSecretRef(regCluster)}
+	if len(regCluster.Status.ImportCommandRef.Name) != 0 && regCluster.Status.ImportCommandRef != importSecretRefs[0] {
+		importSecretRefs = append(importSecretRefs, regCluster.Status.ImportCommandRef)
+	}
+	deleted := true
+	for _, importSecretRef := range importSecretRefs {
+		importSecret, err := r.ComputeKubeClient.CoreV1().Secrets(importSecretRef.Namespace).Get(computeCtx, importSecretRef.Name, metav1.GetOptions{})
+		switch {
+		case k8serrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return false, giterrors.WithStack(err)
+		}
+		if importSecret.DeletionTimestamp != nil {
+			r.Log.V(1).Info("import secret not yet deleted", "namespace", importSecretRef.Namespace, "name", importSecretRef.Name)
+			deleted = false
+			continue
+		}
+		r.Log.Info("delete import secret", "namespace", importSecretRef.Namespace, "name", importSecretRef.Name)
+		err = r.ComputeKubeClient.CoreV1().Secrets(importSecretRef.Namespace).Delete(computeCtx, importSecretRef.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return false, giterrors.WithStack(err)
+		}
+		// A secret with finalizers is only marked for deletion
+		if len(importSecret.Finalizers) != 0 {
+			deleted = false
+		}
+	}
+	return deleted, nil
+}
+
+// syncServiceAccount syncs the kcp-syncer ServiceAccount and RBAC of the SyncTarget in the location workspace and
+// returns its token secret. It returns a nil secret, and no error, while the SyncTarget doesn't exist.
+func (r *RegisteredClusterReconciler) syncServiceAccount(computeCtx context.Context,
+	hubCtx context.Context,
+	regCluster *singaporev1alpha1.RegisteredCluster,
+	locationWorkspace string,
+	managedCluster *clusterapiv1.ManagedCluster,
+	hubCluster *helpers.HubInstance) (*corev1.Secret, error) {
+
+	r.Log.V(2).Info("syncServiceAccount",
 		"registered cluster", regCluster.Name,
 		"location", regCluster.Spec.Location)
 
+	locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
+	syncTarget, err := r.getSyncTarget(locationContext, regCluster)
+	if err != nil {
+		return nil, giterrors.WithStack(err)
+	}
+	if syncTarget == nil {
+		// The SyncTarget is created once the cluster joined, the kcp-syncer is synced after it
+		r.Log.V(2).Info("synctarget not created yet, skip the kcp-syncer service account",
+			"registered cluster", regCluster.Name,
+			"location workspace", locationWorkspace)
+		return nil, nil
+	}
+
 	// Create the ServiceAccount if it doesn't yet exist
-	saName := helpers.GetSyncerServiceAccountName()
+	saName := helpers.GetSyncerServiceAccountName(syncTarget.GetName())
+
+	saNamespace := getSyncerServiceAccountNamespace(regCluster)
 
-	// sa, err := r.ComputeKubeClient.Cluster(logicalcluster.New(regCluster.Spec.Location)).CoreV1().ServiceAccounts("default").Get(ctx, saName, metav1.GetOptions{})
-	locationContext := logicalcluster.WithCluster(computeContext, logicalcluster.New(locationWorkspace))
-	sa, err := r.ComputeKubeClient.CoreV1().ServiceAccounts("default").Get(locationContext, saName, metav1.GetOptions{})
+	sa, err := r.ComputeKubeClient.CoreV1().ServiceAccounts(saNamespace).Get(locationContext, saName, metav1.GetOptions{})
 	if err != nil {
 		if !k8serrors.IsNotFound(err) {
-			return "", err
+			return nil, err
+		}
+
+		if _, err := r.ComputeKubeClient.CoreV1().Namespaces().Get(locationContext, saNamespace, metav1.GetOptions{}); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, fmt.Errorf("the kcp-syncer service account namespace %s doesn't exist in location workspace %s", saNamespace, locationWorkspace)
+			}
+			return nil, err
 		}
 
 		sa = &corev1.ServiceAccount{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: saName,
+				Name:      saName,
+				Namespace: saNamespace,
 			},
 		}
 		r.Log.V(2).Info("syncServiceAccount",
 			"creating service account", regCluster.Name)
-		sa, err = r.ComputeKubeClient.CoreV1().ServiceAccounts("default").Create(locationContext, sa, metav1.CreateOptions{})
+		sa, err = r.ComputeKubeClient.CoreV1().ServiceAccounts(saNamespace).Create(locationContext, sa, metav1.CreateOptions{})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 	}
 
-	// Sync the ClusterRole and ClusterRoleBinding
-
-	// applier := apply.NewApplierBuilder().
-	// 	WithClient(r.ComputeKubeClient,
-	// 		r.ComputeAPIExtensionClient,
-	// 		r.ComputeDynamicClient).
-	// 	// WithOwner(regCluster, false, true, r.Scheme). //TODO - add owner synctarget
-	// 	WithContext(locationContext).
-	// 	Build()
-
-	// readerDeploy := resources.GetScenarioResourcesReader()
-
-	// files := []string{
-	// 	"cluster-registration/kcp_syncer_clusterrole.yaml",
-	// 	"cluster-registration/kcp_syncer_clusterrolebinding.yaml",
-	// }
-
-	// values := struct {
-	// 	KcpSyncerName      string
-	// 	SyncTargetName     string
-	// 	ServiceAccountName string
-	// }{
-	// 	KcpSyncerName:      helpers.GetSyncerName(regCluster.Name),
-	// 	SyncTargetName:     regCluster.Name, // TODO - Get this from SyncTarget.Name
-	// 	ServiceAccountName: saName,
-	// }
-	// fmt.Println("Sleep Start.....")
-
-	// // Calling Sleep method so I can see what the KCP log is doing...
-	// time.Sleep(10 * time.Second)
+	// Sync the ClusterRole and ClusterRoleBinding of the kcp-syncer in the location workspace
+	applier := apply.NewApplierBuilder().
+		WithClient(r.ComputeKubeClient,
+			r.ComputeAPIExtensionClient,
+			r.ComputeDynamicClient).
+		WithContext(locationContext).
+		Build()
 
-	// // Printed after sleep is over
-	// fmt.Println("Sleep Over.....")
-	// _, err = applier.ApplyDirectly(readerDeploy, values, false, "", files...)
-	// fmt.Println("AFTER Sleep Start.....")
+	readerDeploy := resources.GetScenarioResourcesReader()
 
-	// // Calling Sleep method
-	// time.Sleep(10 * time.Second)
+	files := []string{
+		"cluster-registration/kcp_syncer_clusterrole.yaml",
+		"cluster-registration/kcp_syncer_clusterrolebinding.yaml",
+	}
 
-	// Printed after sleep is over
-	r.Log.V(1).Info("SKIPPED create clusterrole and clusterrolebinding for now... permission not yet allowed",
-		"cluster", logicalcluster.From(regCluster).String(),
-		"namespace", regCluster.Namespace,
-		"name", regCluster.Name)
-	if err != nil {
-		return "", giterrors.WithStack(err)
+	values := struct {
+		KcpSyncerName           string
+		SyncTargetName          string
+		ServiceAccountName      string
+		ServiceAccountNamespace string
+	}{
+		KcpSyncerName:           helpers.GetSyncerName(syncTarget),
+		SyncTargetName:          regCluster.Name, // TODO - Get this from SyncTarget.Name
+		ServiceAccountName:      saName,
+		ServiceAccountNamespace: saNamespace,
 	}
 
-	// Return the ServiceAccount token
-	token, err := r.getKcpSyncerSAToken(computeContext, regCluster, locationWorkspace, sa)
-	return token, err
+	r.Log.V(2).Info("syncServiceAccount",
+		"apply clusterrole and clusterrolebinding", values.KcpSyncerName,
+		"location", locationWorkspace)
+	if _, err := applier.ApplyDirectly(readerDeploy, values, false, "", files...); err != nil {
+		return nil, giterrors.WithStack(err)
+	}
 
+	// Return the ServiceAccount token secret
+	return r.getKcpSyncerSAToken(computeCtx, regCluster, locationWorkspace, sa)
 }
 
-func (r *RegisteredClusterReconciler) getKcpSyncerSAToken(computeContext context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, sa *corev1.ServiceAccount) (string, error) {
+// getKcpSyncerSAToken returns the token secret of the kcp-syncer ServiceAccount. The legacy token secrets referenced by
+// the ServiceAccount are looked up first, then the ones annotated with the ServiceAccount name, as a rotated token is
+// not always referenced. Legacy token secrets are no longer generated from kubernetes 1.24, if the ServiceAccount has
+// none a bound token is minted with the TokenRequest API.
+func (r *RegisteredClusterReconciler) getKcpSyncerSAToken(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, sa *corev1.ServiceAccount) (*corev1.Secret, error) {
 
 	r.Log.V(2).Info("getKcpSyncerSAToken",
 		"service account", sa.Name)
 
-	saName := helpers.GetSyncerServiceAccountName()
-	locationContext := logicalcluster.WithCluster(computeContext, logicalcluster.New(locationWorkspace))
+	locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
 
 	for _, secretRef := range sa.Secrets {
 		r.Log.V(4).Info("checking secret",
 			"secret", secretRef.Name)
-		if !strings.HasPrefix(secretRef.Name, saName) {
+		if !strings.HasPrefix(secretRef.Name, sa.Name) {
 			continue
 		}
 		r.Log.V(4).Info("reading secret",
 			"secret", secretRef.Name)
 
-		secret, err := r.ComputeKubeClient.CoreV1().Secrets("default").Get(locationContext, secretRef.Name, metav1.GetOptions{})
+		secret, err := r.ComputeKubeClient.CoreV1().Secrets(sa.Namespace).Get(locationContext, secretRef.Name, metav1.GetOptions{})
 		if err != nil {
 			r.Log.Error(err,
 				"secret", secretRef.Name)
@@ -619,27 +1511,140 @@ func (r *RegisteredClusterReconciler) getKcpSyncerSAToken(computeContext context
 		r.Log.V(4).Info("read secret",
 			"secret", secretRef.Name)
 
-		if secret.Type != corev1.SecretTypeServiceAccountToken {
-			r.Log.V(4).Info("wronog secret type",
-				"type", secret.Type)
-
-			continue
+		if isServiceAccountTokenSecret(secret, sa.Name) {
+			return secret, nil
 		}
+	}
 
-		token, ok := secret.Data["token"]
-		if !ok {
-			r.Log.V(4).Info("wrong data",
-				"data", secret.Data)
+	secrets, err := r.ComputeKubeClient.CoreV1().Secrets(sa.Namespace).List(locationContext, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("type=%s", corev1.SecretTypeServiceAccountToken),
+	})
+	if err != nil {
+		return nil, giterrors.WithStack(err)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Annotations[corev1.ServiceAccountNameKey] != sa.Name || secret.DeletionTimestamp != nil {
 			continue
 		}
-		if len(token) == 0 {
-			continue
+		if isServiceAccountTokenSecret(secret, sa.Name) {
+			return secret, nil
 		}
+	}
+
+	return r.getKcpSyncerBoundToken(locationContext, regCluster, locationWorkspace, sa)
+}
+
+// getClusterDeletionRequeue returns the delay before the deletion of the ManagedCluster is checked again.
+func (r *RegisteredClusterReconciler) getClusterDeletionRequeue() time.Duration {
+	if r.ClusterDeletionRequeue == 0 {
+		return defaultClusterDeletionRequeue
+	}
+	return r.ClusterDeletionRequeue
+}
+
+// getReconcileTimeout returns the deadline of the compute and hub API calls of a reconcile.
+func (r *RegisteredClusterReconciler) getReconcileTimeout() time.Duration {
+	if r.ReconcileTimeout == 0 {
+		return defaultReconcileTimeout
+	}
+	return r.ReconcileTimeout
+}
+
+// getWorkDeletionRequeue returns the delay before the deletion of the kcp-syncer manifestworks is checked again.
+func (r *RegisteredClusterReconciler) getWorkDeletionRequeue() time.Duration {
+	if r.WorkDeletionRequeue == 0 {
+		return defaultWorkDeletionRequeue
+	}
+	return r.WorkDeletionRequeue
+}
+
+// getSyncerTokenExpiration returns the expiration of the bound tokens minted for the kcp-syncer.
+func (r *RegisteredClusterReconciler) getSyncerTokenExpiration() time.Duration {
+	if r.SyncerTokenExpiration == 0 {
+		return helpers.DefaultSyncerTokenExpiration
+	}
+	return r.SyncerTokenExpiration
+}
+
+// getKcpSyncerBoundToken returns the secret holding the bound token of the kcp-syncer ServiceAccount. A bound token
+// is not stored by kubernetes, the minted token is kept in a secret next to the ServiceAccount so the same token is
+// injected in the manifestwork until 80% of its lifetime is elapsed, then a new token is minted.
+func (r *RegisteredClusterReconciler) getKcpSyncerBoundToken(locationContext context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, sa *corev1.ServiceAccount) (*corev1.Secret, error) {
+	secretName := helpers.GetSyncerBoundTokenSecretName(sa.Name)
+	secret, err := r.ComputeKubeClient.CoreV1().Secrets(sa.Namespace).Get(locationContext, secretName, metav1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		secret = nil
+	case err != nil:
+		return nil, giterrors.WithStack(err)
+	}
+
+	expiration := r.getSyncerTokenExpiration()
+	if secret != nil && len(secret.Data[corev1.ServiceAccountTokenKey]) != 0 &&
+		!helpers.IsSyncerTokenExpiring(helpers.GetSyncerTokenExpiration(secret), expiration, time.Now()) {
+		return secret, nil
+	}
 
-		return string(token), nil
+	expirationSeconds := int64(expiration.Seconds())
+	tokenRequest, err := r.ComputeKubeClient.CoreV1().ServiceAccounts(sa.Namespace).CreateToken(locationContext, sa.Name,
+		&authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				ExpirationSeconds: &expirationSeconds,
+			},
+		}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, giterrors.WithStack(err)
 	}
+	r.Log.Info("minted a bound token for the kcp-syncer service account",
+		"namespace", regCluster.Namespace,
+		"name", regCluster.Name,
+		"location", locationWorkspace,
+		"expiration", tokenRequest.Status.ExpirationTimestamp)
 
-	return "", fmt.Errorf("failed to get the token of workspace sa %s in namespace default", saName) // TODO - better error with more specific context
+	annotations := map[string]string{
+		helpers.SyncerTokenExpirationAnnotation: tokenRequest.Status.ExpirationTimestamp.UTC().Format(time.RFC3339),
+	}
+	data := map[string][]byte{
+		corev1.ServiceAccountTokenKey: []byte(tokenRequest.Status.Token),
+	}
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secretName,
+				Namespace:   sa.Namespace,
+				Annotations: annotations,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: data,
+		}
+		secret, err = r.ComputeKubeClient.CoreV1().Secrets(sa.Namespace).Create(locationContext, secret, metav1.CreateOptions{})
+	} else {
+		secret = secret.DeepCopy()
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		for key, value := range annotations {
+			secret.Annotations[key] = value
+		}
+		secret.Data = data
+		secret, err = r.ComputeKubeClient.CoreV1().Secrets(sa.Namespace).Update(locationContext, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, giterrors.WithStack(err)
+	}
+	return secret, nil
+}
+
+// isServiceAccountTokenSecret returns true if the secret is a token secret of the ServiceAccount with a token issued.
+func isServiceAccountTokenSecret(secret *corev1.Secret, saName string) bool {
+	if secret.Type != corev1.SecretTypeServiceAccountToken {
+		return false
+	}
+	if serviceAccountName, ok := secret.Annotations[corev1.ServiceAccountNameKey]; ok && serviceAccountName != saName {
+		return false
+	}
+	return len(secret.Data[corev1.ServiceAccountTokenKey]) != 0
 }
 
 func getSyncerImage() string {
@@ -650,7 +1655,158 @@ func getSyncerImage() string {
 	return defaultSyncerImage
 }
 
-func (r *RegisteredClusterReconciler) syncKcpSyncer(computeContext context.Context, ctx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance, token string) error {
+// getRegisteredClusterSyncerImage returns the kcp-syncer image of the RegisteredCluster spec or of its syncer channel.
+// The default image is returned if the RegisteredCluster has no image nor channel or if the channel is not mapped.
+func (r *RegisteredClusterReconciler) getRegisteredClusterSyncerImage(regCluster *singaporev1alpha1.RegisteredCluster) string {
+	if len(regCluster.Spec.SyncerImage) != 0 {
+		return regCluster.Spec.SyncerImage
+	}
+	channel := regCluster.GetAnnotations()[SyncerChannelAnnotation]
+	if len(channel) == 0 {
+		return getSyncerImage()
+	}
+	if image, ok := r.SyncerChannelImages[channel]; ok && len(image) != 0 {
+		return image
+	}
+	r.Log.Info("syncer channel not mapped to an image, using the default image",
+		"namespace", regCluster.Namespace,
+		"name", regCluster.Name,
+		"channel", channel)
+	return getSyncerImage()
+}
+
+// deleteLegacyKcpSyncerServiceAccount deletes the kcp-syncer ServiceAccount shared by the kcp-syncers of the location
+// workspace in previous versions, and its bound token secret. The legacy token secrets are deleted with it.
+// The deletion is done once per location workspace namespace, previous versions are no longer running.
+func (r *RegisteredClusterReconciler) deleteLegacyKcpSyncerServiceAccount(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string) error {
+	saNamespace := getSyncerServiceAccountNamespace(regCluster)
+	key := locationWorkspace + "/" + saNamespace
+	if _, ok := r.legacySyncerServiceAccountsDeleted.Load(key); ok {
+		return nil
+	}
+	locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
+	err := r.ComputeKubeClient.CoreV1().Secrets(saNamespace).Delete(locationContext,
+		helpers.GetSyncerBoundTokenSecretName(legacySyncerServiceAccountName), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+	err = r.ComputeKubeClient.CoreV1().ServiceAccounts(saNamespace).Delete(locationContext, legacySyncerServiceAccountName, metav1.DeleteOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+	case err != nil:
+		return giterrors.WithStack(err)
+	default:
+		r.Log.Info("deleted the legacy kcp-syncer service account", "name", legacySyncerServiceAccountName, "location", locationWorkspace)
+	}
+	r.legacySyncerServiceAccountsDeleted.Store(key, true)
+	return nil
+}
+
+// getSyncerServiceAccountNamespace returns the namespace of the kcp-syncer ServiceAccount of the RegisteredCluster,
+// default if not set.
+func getSyncerServiceAccountNamespace(regCluster *singaporev1alpha1.RegisteredCluster) string {
+	if len(regCluster.Spec.SyncerServiceAccountNamespace) == 0 {
+		return "default"
+	}
+	return regCluster.Spec.SyncerServiceAccountNamespace
+}
+
+// getSyncerResources returns the resource requests and limits of the kcp-syncer container, the defaults
+// overridden by the ones of the RegisteredCluster spec.
+func getSyncerResources(regCluster *singaporev1alpha1.RegisteredCluster) corev1.ResourceRequirements {
+	syncerResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(defaultSyncerCPURequest),
+			corev1.ResourceMemory: resource.MustParse(defaultSyncerMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(defaultSyncerCPULimit),
+			corev1.ResourceMemory: resource.MustParse(defaultSyncerMemoryLimit),
+		},
+	}
+	if regCluster.Spec.SyncerResources == nil {
+		return syncerResources
+	}
+	for name, quantity := range regCluster.Spec.SyncerResources.Requests {
+		syncerResources.Requests[name] = quantity
+	}
+	for name, quantity := range regCluster.Spec.SyncerResources.Limits {
+		syncerResources.Limits[name] = quantity
+	}
+	return syncerResources
+}
+
+// getSyncerReplicas returns the number of kcp-syncer replicas of the RegisteredCluster, 1 if not set.
+func getSyncerReplicas(regCluster *singaporev1alpha1.RegisteredCluster) int32 {
+	if regCluster.Spec.SyncerReplicas == nil || *regCluster.Spec.SyncerReplicas < 1 {
+		return 1
+	}
+	return *regCluster.Spec.SyncerReplicas
+}
+
+// kcpSyncerValues are the values of the kcp-syncer manifestwork template of a location
+type kcpSyncerValues struct {
+	KcpSyncerName                   string
+	KcpToken                        string
+	KcpTokenHash                    string
+	KcpServer                       string
+	SyncTargetName                  string
+	ManifestWorkNamespace           string
+	RegisteredClusterNameLabel      string
+	RegisteredClusterNamespaceLabel string
+	RegisteredClusterName           string
+	RegisteredClusterNamespace      string
+	ClusterNameAnnotation           string
+	RegisteredClusterClusterName    string
+	LogicalClusterLabel             string
+	LogicalCluster                  string
+	Image                           string
+	DNSConfig                       *corev1.PodDNSConfig
+	NodeSelector                    map[string]string
+	Tolerations                     []corev1.Toleration
+	PullSecretName                  string
+	PullSecretData                  string
+	ProxyEnv                        []corev1.EnvVar
+	Replicas                        int32
+	Resources                       corev1.ResourceRequirements
+}
+
+// getKcpSyncerValues returns the values of the kcp-syncer manifestwork template of the location workspace, the pull
+// secret is added by the caller. The kcp-syncer gets the base URL and the location as its logical cluster, it
+// connects to the location workspace URL and the base URL must not contain a workspace path.
+func (r *RegisteredClusterReconciler) getKcpSyncerValues(regCluster *singaporev1alpha1.RegisteredCluster,
+	locationWorkspace string,
+	managedCluster *clusterapiv1.ManagedCluster,
+	hubCluster *helpers.HubInstance,
+	syncerName string,
+	token string,
+	tokenHash string) kcpSyncerValues {
+	return kcpSyncerValues{
+		KcpSyncerName:                   syncerName,
+		KcpToken:                        token,
+		KcpTokenHash:                    tokenHash,
+		KcpServer:                       r.kcpServer,
+		SyncTargetName:                  regCluster.Name, // TODO - Get this from SyncTarget.Name
+		ManifestWorkNamespace:           r.getManifestWorkNamespace(managedCluster),
+		RegisteredClusterNameLabel:      RegisteredClusterNamelabel,
+		RegisteredClusterNamespaceLabel: RegisteredClusterNamespacelabel,
+		RegisteredClusterName:           regCluster.Name,
+		RegisteredClusterNamespace:      regCluster.Namespace,
+		ClusterNameAnnotation:           ClusterNameAnnotation,
+		RegisteredClusterClusterName:    managedCluster.Annotations[ClusterNameAnnotation],
+		LogicalCluster:                  locationWorkspace,
+		LogicalClusterLabel:             strings.ReplaceAll(locationWorkspace, ":", "_"),
+		Image:                           r.getRegisteredClusterSyncerImage(regCluster),
+		DNSConfig:                       regCluster.Spec.SyncerDNSConfig,
+		NodeSelector:                    regCluster.Spec.SyncerNodeSelector,
+		Tolerations:                     regCluster.Spec.SyncerTolerations,
+		ProxyEnv:                        helpers.GetSyncerProxyEnv(regCluster.Spec.SyncerProxy, hubCluster.HubConfig.Spec.SyncerProxy),
+		Replicas:                        getSyncerReplicas(regCluster),
+		Resources:                       getSyncerResources(regCluster),
+	}
+}
+
+func (r *RegisteredClusterReconciler) syncKcpSyncer(computeCtx context.Context, hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance, tokenSecret *corev1.Secret, forceResync bool) error {
 	logger := r.Log.WithName("syncKcpSyncer").WithValues("namespace", regCluster.Namespace, "name", regCluster.Name, "managed cluster name", managedCluster.Name)
 
 	// If cluster has joined, sync the ManifestWork to create the kcp-syncer deployment and supporting resources
@@ -658,9 +1814,26 @@ func (r *RegisteredClusterReconciler) syncKcpSyncer(computeContext context.Conte
 
 		readerDeploy := resources.GetScenarioResourcesReader()
 
+		// A rotated or renewed token must reach the kcp-syncer before the previous one is revoked or expires
+		token := string(tokenSecret.Data[corev1.ServiceAccountTokenKey])
+		tokenStatus := singaporev1alpha1.SyncerTokenStatus{
+			LocationPath:   locationWorkspace,
+			SecretName:     tokenSecret.Name,
+			TokenHash:      helpers.GetSyncerTokenHash(token),
+			ExpirationTime: helpers.GetSyncerTokenExpiration(tokenSecret),
+		}
+		lastTokenStatus := helpers.FindSyncerTokenStatus(regCluster.Status.SyncerTokens, locationWorkspace)
+		tokenRotated := lastTokenStatus != nil && lastTokenStatus.TokenHash != tokenStatus.TokenHash
+
 		applier := hubCluster.ApplierBuilder.Build()
+		if forceResync || tokenRotated {
+			// The hub applier cache skips the manifestwork update when it was already applied,
+			// use a copy of the builder with an empty cache.
+			applierBuilder := *hubCluster.ApplierBuilder
+			applier = applierBuilder.WithCache(apply.NewResourceCache()).Build()
+		}
 
-		locationContext := logicalcluster.WithCluster(computeContext, logicalcluster.New(locationWorkspace))
+		locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
 		syncTarget, err := r.getSyncTarget(locationContext, regCluster)
 		if err != nil {
 			return err
@@ -668,136 +1841,666 @@ func (r *RegisteredClusterReconciler) syncKcpSyncer(computeContext context.Conte
 
 		syncerName := helpers.GetSyncerName(syncTarget)
 
-		kcpURL, err := url.Parse(r.ComputeConfig.Host)
+		logger.V(2).Info("syncKcpSyncer", "kcp server", r.kcpServer, "reg cluster location", locationWorkspace)
+
+		values := r.getKcpSyncerValues(regCluster, locationWorkspace, managedCluster, hubCluster, syncerName, token, tokenStatus.TokenHash)
+
+		// The pull secret is delivered by the manifestwork, so it is removed with the kcp-syncer
+		pullSecret, err := r.getSyncerImagePullSecret(computeCtx, hubCtx, regCluster, hubCluster)
 		if err != nil {
+			r.Recorder.Event(regCluster, corev1.EventTypeWarning, "SyncerImagePullSecretInvalid", err.Error())
 			return err
 		}
-
-		logger.V(2).Info("syncKcpSyncer", "url path", kcpURL.Path)
-		logger.V(2).Info("syncKcpSyncer", "reg cluster location", locationWorkspace)
-
-		values := struct {
-			KcpSyncerName                   string
-			KcpToken                        string
-			KcpServer                       string
-			SyncTargetName                  string
-			ManagedClusterName              string
-			RegisteredClusterNameLabel      string
-			RegisteredClusterNamespaceLabel string
-			RegisteredClusterName           string
-			RegisteredClusterNamespace      string
-			ClusterNameAnnotation           string
-			RegisteredClusterClusterName    string
-			LogicalClusterLabel             string
-			LogicalCluster                  string
-			Image                           string
-		}{
-			KcpSyncerName:                   syncerName,
-			KcpToken:                        token,
-			KcpServer:                       fmt.Sprintf("%s://%s", kcpURL.Scheme, kcpURL.Host),
-			SyncTargetName:                  regCluster.Name, // TODO - Get this from SyncTarget.Name
-			ManagedClusterName:              managedCluster.Name,
-			RegisteredClusterNameLabel:      RegisteredClusterNamelabel,
-			RegisteredClusterNamespaceLabel: RegisteredClusterNamespacelabel,
-			RegisteredClusterName:           regCluster.Name,
-			RegisteredClusterNamespace:      regCluster.Namespace,
-			ClusterNameAnnotation:           ClusterNameAnnotation,
-			RegisteredClusterClusterName:    managedCluster.Annotations[ClusterNameAnnotation],
-			LogicalCluster:                  locationWorkspace,
-			LogicalClusterLabel:             strings.ReplaceAll(locationWorkspace, ":", "_"),
-			Image:                           getSyncerImage(),
+		if pullSecret != nil {
+			values.PullSecretName = pullSecret.Name
+			values.PullSecretData = base64.StdEncoding.EncodeToString(pullSecret.Data[corev1.DockerConfigJsonKey])
 		}
 
 		logger.V(2).Info("values", "Values", values)
 
+		if err := r.syncKcpServerStatus(computeCtx, regCluster, values.KcpServer); err != nil {
+			return err
+		}
+
+		if err := r.syncSyncerVersionStatus(computeCtx, regCluster, locationWorkspace, values.Image); err != nil {
+			return err
+		}
+
+		syncerTemplates, err := r.getSyncerTemplates(hubCtx, regCluster, hubCluster)
+		if err != nil {
+			return err
+		}
+		templateReader, syncerTemplate, err := helpers.GetSyncerTemplate(readerDeploy, syncerTemplates, regCluster.Spec.SyncerTemplate)
+		if err != nil {
+			r.Recorder.Event(regCluster, corev1.EventTypeWarning, "SyncerTemplateNotFound", err.Error())
+			return giterrors.WithStack(err)
+		}
+
 		files := []string{
-			"cluster-registration/kcp_syncer_manifestwork.yaml",
+			syncerTemplate,
 		}
 
-		_, err = applier.ApplyCustomResources(readerDeploy, values, false, "", files...)
+		_, err = applier.ApplyCustomResources(templateReader, values, false, "", files...)
 		if err != nil {
+			r.Recorder.Event(regCluster, corev1.EventTypeWarning, "SyncerManifestWorkFailed",
+				fmt.Sprintf("Failed to apply the kcp-syncer manifestwork %s/%s: %s", values.ManifestWorkNamespace, values.KcpSyncerName, err.Error()))
 			return giterrors.WithStack(err)
 		}
+		if regCluster.Status.SyncerLastAppliedTime == nil || forceResync {
+			r.Recorder.Event(regCluster, corev1.EventTypeNormal, "SyncerManifestWorkApplied",
+				fmt.Sprintf("The kcp-syncer manifestwork %s/%s was applied for location %s", values.ManifestWorkNamespace, values.KcpSyncerName, locationWorkspace))
+		}
+
+		if err := r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+			"syncerLastAppliedTime": metav1.Now(),
+			"syncerImage":           values.Image,
+		}); err != nil {
+			return err
+		}
+
+		if lastTokenStatus == nil || tokenRotated || lastTokenStatus.SecretName != tokenStatus.SecretName {
+			if tokenRotated {
+				r.Recorder.Event(regCluster, corev1.EventTypeNormal, "SyncerTokenRotated",
+					fmt.Sprintf("The rotated kcp-syncer token of secret %s was injected in the manifestwork %s/%s for location %s",
+						tokenStatus.SecretName, values.ManifestWorkNamespace, values.KcpSyncerName, locationWorkspace))
+			}
+			now := metav1.Now()
+			tokenStatus.LastInjectedTime = &now
+			regCluster.Status.SyncerTokens = helpers.SetSyncerTokenStatus(regCluster.Status.SyncerTokens, tokenStatus)
+			if err := r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+				"syncerTokens": regCluster.Status.SyncerTokens,
+			}); err != nil {
+				return err
+			}
+		}
 
 		work := &manifestworkv1.ManifestWork{}
 
-		err = hubCluster.Client.Get(ctx,
-			types.NamespacedName{Name: values.KcpSyncerName, Namespace: managedCluster.Name},
+		err = hubCluster.Client.Get(hubCtx,
+			types.NamespacedName{Name: values.KcpSyncerName, Namespace: values.ManifestWorkNamespace},
 			work)
 
 		if err != nil {
 			return giterrors.WithStack(err)
 		}
 
-		if status, ok := helpers.GetConditionStatus(work.Status.Conditions, string(manifestworkv1.ManifestApplied)); ok && status == metav1.ConditionTrue {
-			logger.V(1).Info("manifestwork applied. TODO: update status...")
-			//TODO - update status
+		// The manifestwork status updates trigger a reconcile, so the condition follows the syncer health
+		syncerCondition := helpers.GetSyncerAvailableCondition(work)
+		logger.V(1).Info("kcp-syncer manifestwork status", "status", syncerCondition.Status, "reason", syncerCondition.Reason)
+		if err := r.patchStatusConditions(computeCtx, regCluster, syncerCondition); err != nil {
+			return err
+		}
+
+		if r.SyncerFailureEvents {
+			r.reportSyncerFailure(regCluster, work, syncerName)
 		}
 	}
 	return nil
 }
 
-func (r *RegisteredClusterReconciler) processRegclusterDeletion(ctx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance) (ctrl.Result, error) {
+// getSyncerImagePullSecret returns the pull secret of the kcp-syncer image of the RegisteredCluster spec, else of
+// its HubConfig spec, or nil if none is set. The secret must be a kubernetes.io/dockerconfigjson secret.
+func (r *RegisteredClusterReconciler) getSyncerImagePullSecret(computeCtx context.Context, hubCtx context.Context,
+	regCluster *singaporev1alpha1.RegisteredCluster,
+	hubCluster *helpers.HubInstance) (*corev1.Secret, error) {
+	var pullSecret *corev1.Secret
+	var err error
+	switch {
+	case len(regCluster.Spec.SyncerImagePullSecretRef.Name) != 0:
+		pullSecret, err = r.ComputeKubeClient.CoreV1().Secrets(regCluster.Namespace).Get(computeCtx,
+			regCluster.Spec.SyncerImagePullSecretRef.Name, metav1.GetOptions{})
+	case len(hubCluster.HubConfig.Spec.SyncerImagePullSecretRef.Name) != 0 && r.KubeClient != nil:
+		pullSecret, err = r.KubeClient.CoreV1().Secrets(hubCluster.HubConfig.Namespace).Get(hubCtx,
+			hubCluster.HubConfig.Spec.SyncerImagePullSecretRef.Name, metav1.GetOptions{})
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, giterrors.WithStack(err)
+	}
+	if pullSecret.Type != corev1.SecretTypeDockerConfigJson || len(pullSecret.Data[corev1.DockerConfigJsonKey]) == 0 {
+		return nil, fmt.Errorf("kcp-syncer image pull secret %s/%s is not a %s secret",
+			pullSecret.Namespace, pullSecret.Name, corev1.SecretTypeDockerConfigJson)
+	}
+	return pullSecret, nil
+}
+
+// getSyncerTemplates returns the syncer templates ConfigMap of the HubConfig when the RegisteredCluster selects a
+// template which is not bundled, or nil if the HubConfig has none.
+func (r *RegisteredClusterReconciler) getSyncerTemplates(hubCtx context.Context,
+	regCluster *singaporev1alpha1.RegisteredCluster,
+	hubCluster *helpers.HubInstance) (*corev1.ConfigMap, error) {
+	if helpers.IsBundledSyncerTemplate(regCluster.Spec.SyncerTemplate) ||
+		len(hubCluster.HubConfig.Spec.SyncerTemplatesRef.Name) == 0 || r.KubeClient == nil {
+		return nil, nil
+	}
+	syncerTemplates, err := r.KubeClient.CoreV1().ConfigMaps(hubCluster.HubConfig.Namespace).Get(hubCtx,
+		hubCluster.HubConfig.Spec.SyncerTemplatesRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, giterrors.WithStack(err)
+	}
+	return syncerTemplates, nil
+}
+
+// invalidLocationRequeuePeriod is the delay before the locations of a RegisteredCluster are checked again.
+const invalidLocationRequeuePeriod = time.Minute
+
+// syncInvalidLocationCondition checks that the location workspaces of the RegisteredCluster can be resolved on the
+// compute server, a location can be resolved when it serves the SyncTargets. It reports the result in the
+// InvalidLocation condition and returns false if a location is invalid.
+func (r *RegisteredClusterReconciler) syncInvalidLocationCondition(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster) (bool, error) {
+	var invalidLocations []string
+	for _, locationWorkspace := range regCluster.Spec.Location {
+		locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
+		served, err := r.servedResources.IsResourceServed(locationContext, r.ComputeKubeClient.Discovery(), locationWorkspace, syncTargetGVR)
+		switch {
+		case k8serrors.IsForbidden(err):
+			// The workspace doesn't exist or doesn't bind the compute-apis APIExport
+		case err != nil:
+			return false, giterrors.WithStack(err)
+		case served:
+			continue
+		}
+		invalidLocations = append(invalidLocations, locationWorkspace)
+	}
+
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionInvalidLocation,
+		Status:  metav1.ConditionFalse,
+		Reason:  "LocationValid",
+		Message: "The location workspaces are resolved on the compute server",
+	}
+	if len(invalidLocations) != 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "InvalidLocation"
+		condition.Message = fmt.Sprintf("The location workspaces %s can not be resolved on the compute server, "+
+			"check they exist and serve the %s resource", strings.Join(invalidLocations, ", "), syncTargetGVR.GroupResource())
+		r.Log.Info("invalid location", "namespace", regCluster.Namespace, "name", regCluster.Name, "locations", invalidLocations)
+		if status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, condition.Type); !ok || status != metav1.ConditionTrue {
+			r.Recorder.Event(regCluster, corev1.EventTypeWarning, "InvalidLocation", condition.Message)
+		}
+	}
+	if current := meta.FindStatusCondition(regCluster.Status.Conditions, condition.Type); current == nil ||
+		current.Status != condition.Status || current.Reason != condition.Reason || current.Message != condition.Message {
+		if err := r.patchStatusConditions(computeCtx, regCluster, condition); err != nil {
+			return false, err
+		}
+	}
+	return len(invalidLocations) == 0, nil
+}
+
+// syncKcpServerStatus reflects the kcp server URL given to the kcp-syncer in the status and warns,
+// with the KcpServerValid condition, if the URL doesn't look right.
+func (r *RegisteredClusterReconciler) syncKcpServerStatus(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, kcpServer string) error {
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionKcpServerValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  "KcpServerValid",
+		Message: fmt.Sprintf("The kcp-syncer connects to %s", kcpServer),
+	}
+	if err := helpers.ValidateKcpServerURL(kcpServer); err != nil {
+		r.Log.Info("invalid kcp server URL", "namespace", regCluster.Namespace, "name", regCluster.Name, "error", err.Error())
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "KcpServerInvalid"
+		condition.Message = err.Error()
+		if status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, condition.Type); !ok || status != metav1.ConditionFalse {
+			r.Recorder.Event(regCluster, corev1.EventTypeWarning, "KcpServerInvalid", err.Error())
+		}
+	}
+	if err := r.patchStatusConditions(computeCtx, regCluster, condition); err != nil {
+		return err
+	}
+	if regCluster.Status.KcpServer == kcpServer {
+		return nil
+	}
+	return r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+		"kcpServer": kcpServer,
+	})
+}
+
+// syncSyncerVersionStatus warns, with the SyncerVersionCompatible condition, if the kcp-syncer image
+// version is known to be incompatible with the kcp server version. The version is requested in the location
+// workspace the kcp-syncer connects to.
+func (r *RegisteredClusterReconciler) syncSyncerVersionStatus(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, syncerImage string) error {
+	condition := metav1.Condition{
+		Type: singaporev1alpha1.RegisteredClusterConditionSyncerVersionCompatible,
+	}
+	locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
+	serverVersion, err := r.serverVersions.Get(locationContext, r.ComputeKubeClient.Discovery(), locationWorkspace)
+	if err != nil {
+		// The kcp-syncer is still deployed, the check is only a warning
+		r.Log.Info("failed to get the kcp server version", "namespace", regCluster.Namespace, "name", regCluster.Name, "error", err.Error())
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "KcpVersionUnknown"
+		condition.Message = fmt.Sprintf("The kcp server version can not be retrieved: %s", err.Error())
+		return r.patchStatusConditions(computeCtx, regCluster, condition)
+	}
+	compatible, known, message := helpers.CheckSyncerVersion(syncerImage, serverVersion.GitVersion)
+	condition.Message = message
+	switch {
+	case !known:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "SyncerVersionUnknown"
+	case compatible:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SyncerVersionCompatible"
+	default:
+		r.Log.Info("incompatible kcp-syncer version", "namespace", regCluster.Namespace, "name", regCluster.Name, "message", message)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncerVersionIncompatible"
+		if status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, condition.Type); !ok || status != metav1.ConditionFalse {
+			r.Recorder.Event(regCluster, corev1.EventTypeWarning, "SyncerVersionIncompatible", message)
+		}
+	}
+	return r.patchStatusConditions(computeCtx, regCluster, condition)
+}
+
+// reportSyncerFailure surfaces the kcp-syncer deployment unavailability, as reported by the
+// manifestwork status feedback, as a warning event on the RegisteredCluster. The termination reason
+// of the kcp-syncer container is not reported, the pods are not manifests of the manifestwork.
+func (r *RegisteredClusterReconciler) reportSyncerFailure(regCluster *singaporev1alpha1.RegisteredCluster, work *manifestworkv1.ManifestWork, syncerName string) {
+	unavailable, ok := helpers.GetFeedbackValue(work, "apps", "deployments", syncerName, "kcp-syncer", "unavailableReplicas")
+	if !ok || unavailable.Integer == nil || *unavailable.Integer == 0 {
+		return
+	}
+	message := fmt.Sprintf("kcp-syncer in namespace %s has %d unavailable replicas (manifestwork %s/%s)",
+		syncerName, *unavailable.Integer, work.Namespace, work.Name)
+	if reason, ok := helpers.GetFeedbackValue(work, "apps", "deployments", syncerName, "kcp-syncer", "availableReason"); ok && reason.String != nil {
+		message = fmt.Sprintf("%s, reason: %s", message, *reason.String)
+	}
+	if availableMessage, ok := helpers.GetFeedbackValue(work, "apps", "deployments", syncerName, "kcp-syncer", "availableMessage"); ok && availableMessage.String != nil {
+		message = fmt.Sprintf("%s, message: %s", message, *availableMessage.String)
+	}
+	r.Log.V(1).Info("kcp-syncer unavailable", "namespace", regCluster.Namespace, "name", regCluster.Name, "message", message)
+	r.Recorder.Event(regCluster, corev1.EventTypeWarning, "SyncerUnavailable", message)
+}
+
+// isRegclusterTeardownComplete checks with fresh reads of the hub that the kcp-syncer manifestworks and the
+// ManagedCluster of a deleted RegisteredCluster are gone, so its finalizer can be removed. A remaining
+// manifestwork not yet being deleted is deleted.
+func (r *RegisteredClusterReconciler) isRegclusterTeardownComplete(hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance) (bool, error) {
+	hubReader := hubCluster.Cluster.GetAPIReader()
+
+	manifestWorks := &manifestworkv1.ManifestWorkList{}
+	if err := hubReader.List(hubCtx, manifestWorks, client.MatchingLabels{
+		RegisteredClusterNamelabel:      regCluster.Name,
+		RegisteredClusterNamespacelabel: regCluster.Namespace,
+	}); err != nil {
+		return false, giterrors.WithStack(err)
+	}
+	for i := range manifestWorks.Items {
+		manifestWork := &manifestWorks.Items[i]
+		// The labels don't hold the workspace of the RegisteredCluster
+		if manifestWork.GetAnnotations()[ClusterNameAnnotation] != logicalcluster.From(regCluster).String() {
+			continue
+		}
+		// A manifestwork whose SyncTarget is gone is not deleted by the location cleanup
+		if manifestWork.DeletionTimestamp == nil {
+			r.Log.Info("delete stranded manifestwork", "name", manifestWork.Name, "namespace", manifestWork.Namespace)
+			if err := hubCluster.Client.Delete(hubCtx, manifestWork); err != nil && !k8serrors.IsNotFound(err) {
+				return false, giterrors.WithStack(err)
+			}
+		}
+		r.Log.V(1).Info("kcp-syncer manifestwork not yet deleted",
+			"name", manifestWork.Name,
+			"namespace", manifestWork.Namespace)
+		return false, nil
+	}
+
+	if len(managedCluster.Name) == 0 {
+		return true, nil
+	}
+	err := hubReader.Get(hubCtx, types.NamespacedName{Name: managedCluster.Name}, &clusterapiv1.ManagedCluster{})
+	switch {
+	case err == nil:
+		r.Log.V(1).Info("managedcluster not yet deleted", "name", managedCluster.Name)
+		return false, nil
+	case !k8serrors.IsNotFound(err):
+		return false, giterrors.WithStack(err)
+	}
+	return true, nil
+}
+
+// deleteKcpSyncerManifestWork deletes the kcp-syncer manifestwork of a location workspace,
+// it returns true while the manifestwork is still being deleted.
+func (r *RegisteredClusterReconciler) deleteKcpSyncerManifestWork(computeCtx context.Context, hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance) (bool, error) {
+	locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
+	syncTarget, err := r.getSyncTarget(locationContext, regCluster)
+	if err != nil {
+		return false, giterrors.WithStack(err)
+	}
+	if syncTarget == nil {
+		return false, nil
+	}
+
+	manifestwork := &manifestworkv1.ManifestWork{}
+	manifestworkName := helpers.GetSyncerName(syncTarget)
+	manifestworkNamespace := r.getManifestWorkNamespace(managedCluster)
+	err = helpers.RetryOnTransientError(hubCtx, hubDeletionBackoff, func() error {
+		return hubCluster.Client.Get(hubCtx,
+			types.NamespacedName{
+				Name:      manifestworkName,
+				Namespace: manifestworkNamespace},
+			manifestwork)
+	})
+	switch {
+	case err == nil:
+		r.Log.Info("delete manifestwork", "name", manifestworkName)
+		if err := helpers.RetryOnTransientError(hubCtx, hubDeletionBackoff, func() error {
+			return client.IgnoreNotFound(hubCluster.Client.Delete(hubCtx, manifestwork))
+		}); err != nil {
+			return false, giterrors.WithStack(err)
+		}
+		if manifestwork.DeletionTimestamp == nil {
+			r.Recorder.Event(regCluster, corev1.EventTypeNormal, "SyncerManifestWorkDeleting",
+				fmt.Sprintf("Deleting the kcp-syncer manifestwork %s/%s", manifestworkNamespace, manifestworkName))
+		}
+		r.Log.Info("waiting manifestwork to be deleted",
+			"name", manifestworkName,
+			"namespace", manifestworkNamespace)
+		return true, nil
+	case !k8serrors.IsNotFound(err):
+		return false, giterrors.WithStack(err)
+	}
+	r.Log.Info("deleted manifestwork", "name", manifestworkName)
+	return false, nil
+}
+
+// removeKcpSyncer removes the kcp-syncer of a RegisteredCluster which no longer deploys it. It returns true
+// while a kcp-syncer manifestwork is being deleted.
+func (r *RegisteredClusterReconciler) removeKcpSyncer(computeCtx context.Context, hubCtx context.Context,
+	regCluster *singaporev1alpha1.RegisteredCluster,
+	managedCluster *clusterapiv1.ManagedCluster,
+	hubCluster *helpers.HubInstance) (bool, error) {
+	for _, locationWorkspace := range regCluster.Spec.Location {
+		deleting, err := r.deleteKcpSyncerManifestWork(computeCtx, hubCtx, regCluster, locationWorkspace, managedCluster, hubCluster)
+		if err != nil || deleting {
+			return deleting, err
+		}
+		if err := r.deleteKcpSyncerRBAC(computeCtx, regCluster, locationWorkspace); err != nil {
+			return false, err
+		}
+		if err := r.deleteSyncTarget(computeCtx, regCluster, locationWorkspace); err != nil {
+			return false, err
+		}
+	}
+	r.Log.Info("kcp-syncer removed, the registeredcluster doesn't deploy it", "namespace", regCluster.Namespace, "name", regCluster.Name)
+	return false, r.patchStatus(computeCtx, regCluster, map[string]interface{}{
+		"syncerLastAppliedTime": nil,
+		"syncerImage":           nil,
+		"syncerTokens":          nil,
+	})
+}
+
+// deleteKcpSyncerRBAC deletes the kcp-syncer ClusterRole, ClusterRoleBinding and ServiceAccount of the location
+// workspace.
+func (r *RegisteredClusterReconciler) deleteKcpSyncerRBAC(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string) error {
+	locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
+	syncTarget, err := r.getSyncTarget(locationContext, regCluster)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	if syncTarget == nil {
+		return nil
+	}
+
+	syncerName := helpers.GetSyncerName(syncTarget)
+	r.Log.Info("delete kcp-syncer clusterrolebinding", "name", syncerName, "location", locationWorkspace)
+	err = r.ComputeKubeClient.RbacV1().ClusterRoleBindings().Delete(locationContext, syncerName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+	r.Log.Info("delete kcp-syncer clusterrole", "name", syncerName, "location", locationWorkspace)
+	err = r.ComputeKubeClient.RbacV1().ClusterRoles().Delete(locationContext, syncerName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+
+	// The ServiceAccount belongs to this kcp-syncer only, its legacy token secrets are deleted with it
+	saName := helpers.GetSyncerServiceAccountName(syncTarget.GetName())
+	saNamespace := getSyncerServiceAccountNamespace(regCluster)
+	r.Log.Info("delete kcp-syncer bound token secret", "name", helpers.GetSyncerBoundTokenSecretName(saName), "location", locationWorkspace)
+	err = r.ComputeKubeClient.CoreV1().Secrets(saNamespace).Delete(locationContext, helpers.GetSyncerBoundTokenSecretName(saName), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+	r.Log.Info("delete kcp-syncer service account", "name", saName, "location", locationWorkspace)
+	err = r.ComputeKubeClient.CoreV1().ServiceAccounts(saNamespace).Delete(locationContext, saName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+	return nil
+}
+
+// deleteSyncTarget deletes the SyncTarget of the location workspace, it must be deleted after the kcp-syncer
+// manifestwork and RBAC as their names are derived from it.
+func (r *RegisteredClusterReconciler) deleteSyncTarget(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, locationWorkspace string) error {
+	locationContext := logicalcluster.WithCluster(computeCtx, logicalcluster.New(locationWorkspace))
+	syncTarget, err := r.getSyncTarget(locationContext, regCluster)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	if syncTarget == nil {
+		return nil
+	}
+	r.Log.Info("delete synctarget", "name", syncTarget.GetName(), "location", locationWorkspace)
+	err = r.ComputeDynamicClient.Resource(syncTargetGVR).Delete(locationContext, syncTarget.GetName(), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+	return nil
+}
+
+// syncSyncerEviction removes the kcp-syncer manifestworks when the ManagedCluster has one of the
+// SyncerEvictionTaints and reports it in the SyncerEvicted condition. It returns true if the cluster is evicted.
+func (r *RegisteredClusterReconciler) syncSyncerEviction(computeCtx context.Context, hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance) (bool, error) {
+	if len(r.SyncerEvictionTaints) == 0 {
+		return false, nil
+	}
+
+	evictionTaint := ""
+	for _, taint := range managedCluster.Spec.Taints {
+		for _, key := range r.SyncerEvictionTaints {
+			if taint.Key == key {
+				evictionTaint = key
+				break
+			}
+		}
+		if len(evictionTaint) != 0 {
+			break
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionSyncerEvicted,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ManagedClusterNotTainted",
+		Message: "The ManagedCluster has no eviction taint",
+	}
+	if len(evictionTaint) != 0 {
+		for _, locationWorkspace := range regCluster.Spec.Location {
+			if _, err := r.deleteKcpSyncerManifestWork(computeCtx, hubCtx, regCluster, locationWorkspace, managedCluster, hubCluster); err != nil {
+				return true, err
+			}
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ManagedClusterTainted"
+		condition.Message = fmt.Sprintf("The ManagedCluster has the eviction taint %s, the kcp-syncer is removed", evictionTaint)
+	}
+
+	if status, ok := helpers.GetConditionStatus(regCluster.Status.Conditions, condition.Type); !ok || status != condition.Status {
+		r.Log.Info("kcp-syncer eviction changed", "namespace", regCluster.Namespace, "name", regCluster.Name, "evicted", condition.Status)
+		if err := r.patchStatusConditions(computeCtx, regCluster, condition); err != nil {
+			return len(evictionTaint) != 0, err
+		}
+	}
+	return len(evictionTaint) != 0, nil
+}
+
+func (r *RegisteredClusterReconciler) processRegclusterDeletion(computeCtx context.Context, hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, managedCluster *clusterapiv1.ManagedCluster, hubCluster *helpers.HubInstance) (ctrl.Result, error) {
+	// Keep the finalizer of a protected RegisteredCluster until the protection is removed
+	if regCluster.GetAnnotations()[DeletionProtectionAnnotation] == "true" {
+		r.Log.Info("registeredcluster is deletion protected, teardown refused",
+			"namespace", regCluster.Namespace,
+			"name", regCluster.Name,
+			"annotation", DeletionProtectionAnnotation)
+		r.Recorder.Event(regCluster, corev1.EventTypeWarning, "DeletionProtected",
+			fmt.Sprintf("The deletion is refused until the %s annotation is removed", DeletionProtectionAnnotation))
+		if err := r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+			Type:    singaporev1alpha1.RegisteredClusterConditionDeletionProtected,
+			Status:  metav1.ConditionTrue,
+			Reason:  "DeletionProtected",
+			Message: fmt.Sprintf("The deletion is refused until the %s annotation is removed", DeletionProtectionAnnotation),
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.Backoff.Resync(deletionProtectionResyncPeriod), nil
+	}
 
 	// TODO - update this
-	if len(regCluster.Spec.Location) > 0 {
+	// The kcp-syncer manifestwork was never created if the kcp-syncer is not deployed
+	if len(regCluster.Spec.Location) > 0 && (helpers.IsSyncerDeployed(regCluster) || regCluster.Status.SyncerLastAppliedTime != nil) {
 		for _, locationWorkspace := range regCluster.Spec.Location {
 
-			locationContext := logicalcluster.WithCluster(ctx, logicalcluster.New(locationWorkspace))
-			syncTarget, err := r.getSyncTarget(locationContext, regCluster)
+			deleting, err := r.deleteKcpSyncerManifestWork(computeCtx, hubCtx, regCluster, locationWorkspace, managedCluster, hubCluster)
 			if err != nil {
-				return ctrl.Result{}, giterrors.WithStack(err)
+				return ctrl.Result{}, err
+			}
+			if deleting {
+				return r.Backoff.Requeue(requeueKey(regCluster), r.getWorkDeletionRequeue()), nil
 			}
 
-			manifestwork := &manifestworkv1.ManifestWork{}
-			manifestworkName := helpers.GetSyncerName(syncTarget)
-			err = hubCluster.Client.Get(ctx,
-				types.NamespacedName{
-					Name:      manifestworkName,
-					Namespace: managedCluster.Name},
-				manifestwork)
-			switch {
-			case err == nil:
-				r.Log.Info("delete manifestwork", "name", manifestworkName)
-				if err := hubCluster.Client.Delete(ctx, manifestwork); err != nil {
-					return ctrl.Result{}, giterrors.WithStack(err)
-				}
-				r.Log.Info("waiting manifestwork to be deleted",
-					"name", manifestworkName,
-					"namespace", managedCluster.Name)
-				return ctrl.Result{Requeue: true, RequeueAfter: 1 * time.Second}, nil
-			case !k8serrors.IsNotFound(err):
+			if err := r.deleteKcpSyncerRBAC(computeCtx, regCluster, locationWorkspace); err != nil {
+				return ctrl.Result{}, err
+			}
 
-				return ctrl.Result{}, giterrors.WithStack(err)
+			if err := r.deleteSyncTarget(computeCtx, regCluster, locationWorkspace); err != nil {
+				return ctrl.Result{}, err
 			}
-			r.Log.Info("deleted manifestwork", "name", manifestworkName)
 		}
 	}
 
 	// TODO - remaining cleanup - https://issues.redhat.com/browse/CMCS-145
 
+	// The finalizer is kept until the import secret is confirmed gone, else it would be orphaned
+	importSecretDeleted, err := r.deleteImportSecret(computeCtx, regCluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !importSecretDeleted {
+		r.Log.Info("waiting import secret to be deleted",
+			"namespace", regCluster.Namespace,
+			"name", regCluster.Name)
+		return r.Backoff.Requeue(requeueKey(regCluster), importSecretDeletionRequeue), nil
+	}
+
 	cluster := &clusterapiv1.ManagedCluster{}
-	err := hubCluster.Client.Get(ctx,
-		types.NamespacedName{
-			Name: managedCluster.Name},
-		cluster)
+	err = helpers.RetryOnTransientError(hubCtx, hubDeletionBackoff, func() error {
+		return hubCluster.Client.Get(hubCtx,
+			types.NamespacedName{
+				Name: managedCluster.Name},
+			cluster)
+	})
 	switch {
 	case err == nil:
 		r.Log.Info("delete managedcluster", "name", managedCluster.Name)
-		if err := hubCluster.Client.Delete(ctx, cluster); err != nil {
+		if err := helpers.RetryOnTransientError(hubCtx, hubDeletionBackoff, func() error {
+			return client.IgnoreNotFound(hubCluster.Client.Delete(hubCtx, cluster))
+		}); err != nil {
 			return ctrl.Result{}, giterrors.WithStack(err)
 		}
+		if cluster.DeletionTimestamp == nil {
+			r.Recorder.Event(regCluster, corev1.EventTypeNormal, "ManagedClusterDeleting",
+				fmt.Sprintf("Deleting the ManagedCluster %s", managedCluster.Name))
+		}
 		r.Log.Info("waiting managedcluster to be deleted",
 			"name", managedCluster.Name)
-		return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
+		return r.Backoff.Requeue(requeueKey(regCluster), r.getClusterDeletionRequeue()), nil
 	case !k8serrors.IsNotFound(err):
 		return ctrl.Result{}, giterrors.WithStack(err)
 	}
 	r.Log.Info("deleted managedcluster", "name", managedCluster.Name)
+	if len(managedCluster.Name) != 0 {
+		r.Recorder.Event(regCluster, corev1.EventTypeNormal, "ManagedClusterDeleted",
+			fmt.Sprintf("The ManagedCluster %s was deleted", managedCluster.Name))
+	}
+
+	if r.DeleteClusterSetBindings {
+		if err := r.deleteManagedClusterSetBindings(hubCtx, regCluster, hubCluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	return ctrl.Result{}, nil
 }
 
+// deleteManagedClusterSetBindings deletes the ManagedClusterSetBindings of the workspace ManagedClusterSet once
+// the last ManagedCluster of the workspace is deleted, so no binding remains dangling for an emptied workspace.
+func (r *RegisteredClusterReconciler) deleteManagedClusterSetBindings(hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, hubCluster *helpers.HubInstance) error {
+	clusterSetName := helpers.ManagedClusterSetNameForWorkspace(logicalcluster.From(regCluster).String())
+	selector, err := getManagedClusterSetSelector(nil, logicalcluster.From(regCluster).String())
+	if err != nil {
+		return err
+	}
+	managedClusterList := &clusterapiv1.ManagedClusterList{}
+	if err := hubCluster.Cluster.GetAPIReader().List(hubCtx, managedClusterList, selector); err != nil {
+		return giterrors.WithStack(err)
+	}
+	if len(managedClusterList.Items) != 0 {
+		r.Log.V(1).Info("managedclusterset still has managedclusters, keeping its bindings",
+			"managedclusterset", clusterSetName,
+			"managedclusters", len(managedClusterList.Items))
+		return nil
+	}
+
+	bindingList := &clusterv1beta1.ManagedClusterSetBindingList{}
+	if err := hubCluster.Cluster.GetAPIReader().List(hubCtx, bindingList); err != nil {
+		return giterrors.WithStack(err)
+	}
+	for i := range bindingList.Items {
+		binding := &bindingList.Items[i]
+		if binding.Spec.ClusterSet != clusterSetName {
+			continue
+		}
+		r.Log.Info("delete managedclustersetbinding", "name", binding.Name, "namespace", binding.Namespace)
+		if err := hubCluster.Client.Delete(hubCtx, binding); err != nil && !k8serrors.IsNotFound(err) {
+			return giterrors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// getRegisteredClusterSelector selects the ManagedClusters of the RegisteredCluster, including the ones labeled
+// with the legacy ManagedClusterSet name of the workspace.
+func getRegisteredClusterSelector(regCluster *singaporev1alpha1.RegisteredCluster, clusterName string) (client.MatchingLabelsSelector, error) {
+	return getManagedClusterSetSelector(map[string]string{
+		RegisteredClusterNamelabel:      regCluster.Name,
+		RegisteredClusterNamespacelabel: regCluster.Namespace,
+		RegisteredClusterUidLabel:       string(regCluster.UID),
+	}, clusterName)
+}
+
+// getManagedClusterSetSelector selects the ManagedClusters with the matchLabels in the ManagedClusterSet of the
+// workspace. The ManagedClusters created before the ManagedClusterSet names were sanitized have the legacy name
+// as their clusterset label value until they are relabeled.
+func getManagedClusterSetSelector(matchLabels map[string]string, clusterName string) (client.MatchingLabelsSelector, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels: matchLabels,
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      ManagedClusterSetlabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values: []string{
+				helpers.ManagedClusterSetNameForWorkspace(clusterName),
+				helpers.LegacyManagedClusterSetName(clusterName),
+			},
+		}},
+	})
+	if err != nil {
+		return client.MatchingLabelsSelector{}, giterrors.WithStack(err)
+	}
+	return client.MatchingLabelsSelector{Selector: selector}, nil
+}
+
 func getRegisteredClusterLabels(regCluster *singaporev1alpha1.RegisteredCluster, clusterName string) map[string]string {
 	return map[string]string{
 		RegisteredClusterNamelabel:      regCluster.Name,
@@ -807,18 +2510,51 @@ func getRegisteredClusterLabels(regCluster *singaporev1alpha1.RegisteredCluster,
 	}
 }
 
-func (r *RegisteredClusterReconciler) createManagedCluster(ctx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, hubCluster *helpers.HubInstance, clusterName string) error {
+func (r *RegisteredClusterReconciler) createManagedCluster(computeCtx context.Context, hubCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, hubCluster *helpers.HubInstance, clusterName string) error {
 	logger := r.Log.WithName("createManagedCluster").WithValues("namespace", regCluster.Namespace, "name", regCluster.Name, "hub", hubCluster.HubConfig.Name)
 	// check if managedcluster is already exists
 	managedClusterList := &clusterapiv1.ManagedClusterList{}
 	labels := getRegisteredClusterLabels(regCluster, clusterName)
-	logger.V(2).Info("get managedclusterlist", "labels", labels)
-	if err := hubCluster.Client.List(ctx, managedClusterList, client.MatchingLabels(labels)); err != nil {
+	selector, err := getRegisteredClusterSelector(regCluster, clusterName)
+	if err != nil {
+		return err
+	}
+	logger.V(2).Info("get managedclusterlist", "selector", selector.String())
+	if err := hubCluster.Client.List(hubCtx, managedClusterList, selector); err != nil {
 		// Error reading the object - requeue the request.
 		return giterrors.WithStack(err)
 	}
 
+	// relabel the managedclusters created with the legacy managedclusterset name
+	for i := range managedClusterList.Items {
+		managedCluster := &managedClusterList.Items[i]
+		if managedCluster.Labels[ManagedClusterSetlabel] == labels[ManagedClusterSetlabel] {
+			continue
+		}
+		logger.Info("update managedcluster clusterset label",
+			"managedcluster", managedCluster.Name,
+			"from", managedCluster.Labels[ManagedClusterSetlabel],
+			"to", labels[ManagedClusterSetlabel])
+		patch := client.MergeFrom(managedCluster.DeepCopy())
+		managedCluster.Labels[ManagedClusterSetlabel] = labels[ManagedClusterSetlabel]
+		if err := hubCluster.Client.Patch(hubCtx, managedCluster, patch); err != nil {
+			return giterrors.WithStack(err)
+		}
+	}
+
 	if len(managedClusterList.Items) < 1 {
+		if managedClusterName := regCluster.GetAnnotations()[AdoptManagedClusterAnnotation]; len(managedClusterName) != 0 {
+			return r.adoptManagedCluster(computeCtx, hubCtx, regCluster, hubCluster, labels, clusterName, managedClusterName)
+		}
+
+		annotations, ignored := helpers.MergeManagedClusterAnnotations(map[string]string{
+			"open-cluster-management/service-name": "compute",
+			ClusterNameAnnotation:                  clusterName,
+		}, regCluster.Spec.ManagedClusterAnnotations)
+		if len(ignored) != 0 {
+			logger.Info("WARNING: the managedClusterAnnotations reserved by the operator are ignored", "annotations", ignored)
+		}
+
 		managedCluster := &clusterapiv1.ManagedCluster{
 			TypeMeta: metav1.TypeMeta{
 				APIVersion: clusterapiv1.SchemeGroupVersion.String(),
@@ -826,24 +2562,126 @@ func (r *RegisteredClusterReconciler) createManagedCluster(ctx context.Context,
 			},
 			ObjectMeta: metav1.ObjectMeta{
 				GenerateName: "registered-cluster-",
-				Labels:       labels,
-				Annotations: map[string]string{
-					"open-cluster-management/service-name": "compute",
-					ClusterNameAnnotation:                  clusterName,
-				},
+				Labels:       getRegisteredClusterLabels(regCluster, clusterName),
+				Annotations:  annotations,
 			},
 			Spec: clusterapiv1.ManagedClusterSpec{
-				HubAcceptsClient: true,
+				HubAcceptsClient:     true,
+				LeaseDurationSeconds: regCluster.Spec.LeaseDurationSeconds,
 			},
 		}
+		// The user labels of the RegisteredCluster are propagated for the hub placements
+		helpers.SyncPropagatedLabels(managedCluster, helpers.GetPropagatedLabels(regCluster.GetLabels(), labels))
+
+		if err := hubCluster.Client.Create(hubCtx, managedCluster, &client.CreateOptions{}); err != nil {
+			r.Recorder.Event(regCluster, corev1.EventTypeWarning, "ManagedClusterCreationFailed",
+				fmt.Sprintf("Failed to create the ManagedCluster on hub %s: %s", hubCluster.HubConfig.Name, err.Error()))
+			return giterrors.WithStack(err)
+		}
+		r.Recorder.Event(regCluster, corev1.EventTypeNormal, "ManagedClusterCreated",
+			fmt.Sprintf("The ManagedCluster %s was created on hub %s", managedCluster.Name, hubCluster.HubConfig.Name))
+
+		// seed the initial claims until the agent reports its own
+		if len(regCluster.Spec.InitialClusterClaims) != 0 {
+			logger.V(1).Info("seed managedcluster initial cluster claims", "managedcluster", managedCluster.Name)
+			managedCluster.Status.ClusterClaims = regCluster.Spec.InitialClusterClaims
+			if err := hubCluster.Client.Status().Update(hubCtx, managedCluster); err != nil {
+				return giterrors.WithStack(err)
+			}
+		}
+		return nil
+	}
 
-		if err := hubCluster.Client.Create(ctx, managedCluster, &client.CreateOptions{}); err != nil {
+	// reconcile the lease duration override of an existing managedcluster
+	selected, _, _ := helpers.SelectManagedCluster(managedClusterList.Items)
+	managedCluster := &selected
+	if regCluster.Spec.LeaseDurationSeconds != 0 && managedCluster.Spec.LeaseDurationSeconds != regCluster.Spec.LeaseDurationSeconds {
+		logger.V(1).Info("update managedcluster lease duration",
+			"managedcluster", managedCluster.Name,
+			"leaseDurationSeconds", regCluster.Spec.LeaseDurationSeconds)
+		patch := client.MergeFrom(managedCluster.DeepCopy())
+		managedCluster.Spec.LeaseDurationSeconds = regCluster.Spec.LeaseDurationSeconds
+		if err := hubCluster.Client.Patch(hubCtx, managedCluster, patch); err != nil {
+			return giterrors.WithStack(err)
+		}
+	}
+
+	// reconcile the propagated labels of an existing managedcluster
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	if helpers.SyncPropagatedLabels(managedCluster, helpers.GetPropagatedLabels(regCluster.GetLabels(), labels)) {
+		logger.V(1).Info("update managedcluster propagated labels", "managedcluster", managedCluster.Name)
+		if err := hubCluster.Client.Patch(hubCtx, managedCluster, patch); err != nil {
 			return giterrors.WithStack(err)
 		}
 	}
 	return nil
 }
 
+// adoptManagedCluster labels an existing ManagedCluster for the RegisteredCluster instead of creating a new one.
+// The adoption is refused if the ManagedCluster already belongs to another RegisteredCluster.
+func (r *RegisteredClusterReconciler) adoptManagedCluster(computeCtx context.Context,
+	hubCtx context.Context,
+	regCluster *singaporev1alpha1.RegisteredCluster,
+	hubCluster *helpers.HubInstance,
+	labels map[string]string,
+	clusterName string,
+	managedClusterName string) error {
+	logger := r.Log.WithName("adoptManagedCluster").WithValues("namespace", regCluster.Namespace, "name", regCluster.Name, "managedcluster", managedClusterName)
+	managedCluster := &clusterapiv1.ManagedCluster{}
+	if err := hubCluster.Client.Get(hubCtx, types.NamespacedName{Name: managedClusterName}, managedCluster); err != nil {
+		return giterrors.WithStack(err)
+	}
+
+	for _, key := range []string{RegisteredClusterNamelabel, RegisteredClusterNamespacelabel, RegisteredClusterUidLabel} {
+		if value, ok := managedCluster.GetLabels()[key]; ok && value != labels[key] {
+			logger.Info("managedcluster belongs to another registeredcluster, adoption refused", "label", key, "value", value)
+			if err := r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+				Type:   singaporev1alpha1.RegisteredClusterConditionManagedClusterAdopted,
+				Status: metav1.ConditionFalse,
+				Reason: "AdoptionConflict",
+				Message: fmt.Sprintf("The ManagedCluster %s already belongs to the RegisteredCluster %s/%s",
+					managedClusterName,
+					managedCluster.GetLabels()[RegisteredClusterNamespacelabel],
+					managedCluster.GetLabels()[RegisteredClusterNamelabel]),
+			}); err != nil {
+				return err
+			}
+			return fmt.Errorf("managedcluster %s already belongs to another registeredcluster", managedClusterName)
+		}
+	}
+
+	logger.Info("adopt managedcluster")
+	patch := client.MergeFrom(managedCluster.DeepCopy())
+	if managedCluster.Labels == nil {
+		managedCluster.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		managedCluster.Labels[k] = v
+	}
+	helpers.SyncPropagatedLabels(managedCluster, helpers.GetPropagatedLabels(regCluster.GetLabels(), labels))
+	if managedCluster.Annotations == nil {
+		managedCluster.Annotations = map[string]string{}
+	}
+	managedCluster.Annotations["open-cluster-management/service-name"] = "compute"
+	managedCluster.Annotations[ClusterNameAnnotation] = clusterName
+	if regCluster.Spec.LeaseDurationSeconds != 0 {
+		managedCluster.Spec.LeaseDurationSeconds = regCluster.Spec.LeaseDurationSeconds
+	}
+	if err := hubCluster.Client.Patch(hubCtx, managedCluster, patch); err != nil {
+		return giterrors.WithStack(err)
+	}
+
+	return r.patchStatusConditions(computeCtx, regCluster, metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionManagedClusterAdopted,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ManagedClusterAdopted",
+		Message: fmt.Sprintf("The existing ManagedCluster %s is adopted", managedClusterName),
+	})
+}
+
+// registeredClusterPredicate filters the RegisteredCluster events.
+// An update is processed only if the status is unchanged, so status writes, including the ones
+// made by this controller, never trigger a reconcile and can not cause reconcile loops.
 func registeredClusterPredicate() predicate.Predicate {
 	return predicate.Predicate(predicate.Funcs{
 		GenericFunc: func(e event.GenericEvent) bool { return false },
@@ -889,6 +2727,7 @@ func managedClusterPredicate() predicate.Predicate {
 				if f(event.ObjectNew) &&
 					(!equality.Semantic.DeepEqual(old.Status, new.Status) ||
 						!equality.Semantic.DeepEqual(old.Spec.ManagedClusterClientConfigs, new.Spec.ManagedClusterClientConfigs) ||
+						!equality.Semantic.DeepEqual(old.Spec.Taints, new.Spec.Taints) ||
 						old.GetLabels()["clusterID"] != new.GetLabels()["clusterID"]) {
 					log := ctrl.Log.WithName("controllers").WithName("RegisteredCluster").WithName("managedClusterPredicate").WithValues("namespace", new.GetNamespace(), "name", new.GetName())
 					log.V(1).Info("process managedcluster update")
@@ -944,46 +2783,89 @@ func manifestWorkPredicate() predicate.Predicate {
 	}
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// registeredClusterRequests maps a hub object to the request of the RegisteredCluster owning it.
+// The hub objects of all the compute workspaces share the same hub caches, the workspace of the
+// owning RegisteredCluster is taken from the ClusterNameAnnotation so the request is reconciled in
+// that workspace. Objects without the workspace are ignored, as they would be reconciled against
+// the wrong workspace.
+func registeredClusterRequests(o client.Object) []reconcile.Request {
+	name := o.GetLabels()[RegisteredClusterNamelabel]
+	namespace := o.GetLabels()[RegisteredClusterNamespacelabel]
+	clusterName := o.GetAnnotations()[ClusterNameAnnotation]
+	if name == "" || namespace == "" || clusterName == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      name,
+				Namespace: namespace,
+			},
+			ClusterName: clusterName,
+		},
+	}
+}
 
+// SetupWithManager sets up the controller with the Manager.
+// The ManagedClusters and ManifestWorks of each hub are watched to reconcile their RegisteredCluster.
+// If no hub is configured yet, the controller only watches the RegisteredClusters, their reconcile fails
+// until a HubConfig is created and its hub is added with addHubCluster, no restart of the operator is needed.
 func (r *RegisteredClusterReconciler) SetupWithManager(mgr ctrl.Manager, scheme *runtime.Scheme) error {
+	kcpServer, err := helpers.KcpServerBaseURL(r.ComputeConfig)
+	if err != nil {
+		return err
+	}
+	r.kcpServer = kcpServer
 
-	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
-		For(&singaporev1alpha1.RegisteredCluster{}, builder.WithPredicates(registeredClusterPredicate()))
-
-	for _, hubCluster := range r.HubClusters {
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&singaporev1alpha1.RegisteredCluster{}, builder.WithPredicates(registeredClusterPredicate())).
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.controller = c
 
-		r.Log.V(1).Info("add watchers for ", "hubConfig.Name", hubCluster.HubConfig.Name)
-		controllerBuilder.Watches(source.NewKindWithCache(&clusterapiv1.ManagedCluster{}, hubCluster.Cluster.GetCache()), handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
-			managedCluster := o.(*clusterapiv1.ManagedCluster)
-			r.Log.Info("Processing ManagedCluster event", "name", managedCluster.Name)
+	r.hubEvents = make(chan event.GenericEvent)
+	if err := c.Watch(&source.Channel{Source: r.hubEvents}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
 
-			req := make([]ctrl.Request, 0)
-			req = append(req, ctrl.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      managedCluster.GetLabels()[RegisteredClusterNamelabel],
-					Namespace: managedCluster.GetLabels()[RegisteredClusterNamespacelabel],
-				},
-				ClusterName: managedCluster.GetAnnotations()[ClusterNameAnnotation],
-			})
-			return req
-		}), builder.WithPredicates(managedClusterPredicate())).
-			Watches(source.NewKindWithCache(&manifestworkv1.ManifestWork{}, hubCluster.Cluster.GetCache()), handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
-				manifestWork := o.(*manifestworkv1.ManifestWork)
-				r.Log.Info("Processing ManifestWork event", "name", manifestWork.Name, "namespace", manifestWork.Namespace)
-
-				req := make([]reconcile.Request, 0)
-				req = append(req, reconcile.Request{
-					NamespacedName: types.NamespacedName{
-						Name:      manifestWork.GetLabels()[RegisteredClusterNamelabel],
-						Namespace: manifestWork.GetLabels()[RegisteredClusterNamespacelabel],
-					},
-					ClusterName: manifestWork.GetAnnotations()[ClusterNameAnnotation],
-				})
-				return req
-			}), builder.WithPredicates(manifestWorkPredicate()))
+	hubClusters := r.getHubClusters()
+	if len(hubClusters) == 0 {
+		r.Log.Info("WARNING: no HubConfig is configured, the RegisteredClusters will not be processed until a valid HubConfig is created")
 	}
+	for _, hubCluster := range hubClusters {
+		if err := r.watchHubCluster(hubCluster); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return controllerBuilder.
-		Complete(r)
+// watchHubCluster watches the ManagedClusters and ManifestWorks of a hub to reconcile their RegisteredCluster.
+func (r *RegisteredClusterReconciler) watchHubCluster(hubCluster helpers.HubInstance) error {
+	r.Log.V(1).Info("add watchers for ", "hubConfig.Name", hubCluster.HubConfig.Name)
+	// The hub name is logged with the events to know which hub produced them
+	hubName := hubCluster.HubConfig.Name
+	if err := r.controller.Watch(source.NewKindWithCache(&clusterapiv1.ManagedCluster{}, hubCluster.Cluster.GetCache()), handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+		managedCluster := o.(*clusterapiv1.ManagedCluster)
+		r.Log.Info("Processing ManagedCluster event",
+			"hub", hubName,
+			"name", managedCluster.Name,
+			"workspace", managedCluster.GetAnnotations()[ClusterNameAnnotation])
+
+		return registeredClusterRequests(managedCluster)
+	}), managedClusterPredicate()); err != nil {
+		return err
+	}
+	return r.controller.Watch(source.NewKindWithCache(&manifestworkv1.ManifestWork{}, hubCluster.Cluster.GetCache()), handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+		manifestWork := o.(*manifestworkv1.ManifestWork)
+		r.Log.Info("Processing ManifestWork event",
+			"hub", hubName,
+			"name", manifestWork.Name,
+			"namespace", manifestWork.Namespace,
+			"workspace", manifestWork.GetAnnotations()[ClusterNameAnnotation])
+
+		return registeredClusterRequests(manifestWork)
+	}), manifestWorkPredicate())
 }
@@ -0,0 +1,72 @@
+// Copyright Red Hat
+
+package registeredcluster
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stolostron/applier/pkg/apply"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/pkg/helpers"
+	"github.com/stolostron/compute-operator/resources"
+)
+
+func TestKcpSyncerValuesNestedLocation(t *testing.T) {
+	// The compute kubeconfig points to a workspace, the kcp-syncer must get the base URL
+	kcpServer, err := helpers.KcpServerBaseURL(&rest.Config{Host: "https://kcp.example.com:6443/clusters/root:org:compute"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	r := &RegisteredClusterReconciler{
+		Log:       logr.Discard(),
+		kcpServer: kcpServer,
+	}
+	regCluster := &singaporev1alpha1.RegisteredCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: "ns1",
+		},
+	}
+	managedCluster := &clusterapiv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "registered-cluster-abcde",
+		},
+	}
+	hubCluster := &helpers.HubInstance{
+		HubConfig: &singaporev1alpha1.HubConfig{},
+	}
+
+	values := r.getKcpSyncerValues(regCluster, "root:org:team:loc", managedCluster, hubCluster, "kcp-syncer-cluster1-abcde", "token", "hash")
+	if values.KcpServer != "https://kcp.example.com:6443" {
+		t.Fatalf(`KcpServer not as expected. Expected https://kcp.example.com:6443, actual %s`, values.KcpServer)
+	}
+	if values.LogicalCluster != "root:org:team:loc" {
+		t.Fatalf(`LogicalCluster not as expected. Expected root:org:team:loc, actual %s`, values.LogicalCluster)
+	}
+
+	templateReader, syncerTemplate, err := helpers.GetSyncerTemplate(resources.GetScenarioResourcesReader(), nil, "")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	applier := apply.NewApplierBuilder().Build()
+	b, err := applier.MustTemplateAsset(templateReader, values, "", syncerTemplate)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	manifestWork := string(b)
+	if !strings.Contains(manifestWork, "server: https://kcp.example.com:6443\n") {
+		t.Fatalf(`Rendered kubeconfig server not as expected. Expected https://kcp.example.com:6443, actual %s`, manifestWork)
+	}
+	if strings.Contains(manifestWork, "/clusters/") {
+		t.Fatalf(`Expected no workspace path in the rendered manifestwork, actual %s`, manifestWork)
+	}
+	if !strings.Contains(manifestWork, `--from_cluster=root:org:team:loc`) {
+		t.Fatalf(`Rendered --from_cluster not as expected. Expected root:org:team:loc, actual %s`, manifestWork)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	giterrors "github.com/pkg/errors"
 
@@ -16,10 +17,13 @@ import (
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/kcp"
 
@@ -43,9 +47,40 @@ var (
 )
 
 type managerOptions struct {
-	metricsAddr          string
-	probeAddr            string
-	enableLeaderElection bool
+	metricsAddr             string
+	probeAddr               string
+	enableLeaderElection    bool
+	leaseDuration           time.Duration
+	renewDeadline           time.Duration
+	importSecretNamespace   string
+	importSecretLabels      map[string]string
+	syncerFailureEvents     bool
+	syncerEvictionTaints    []string
+	gracefulShutdownTimeout time.Duration
+	pprofAddr               string
+	requeueMaxDelay         time.Duration
+	requeueJitter           float64
+	requireWorkspace        bool
+	deleteClusterSetBinding bool
+	propagateConsoleURL     bool
+	importApplyRetries      int
+	importSecretTimeout     time.Duration
+	batchStatusUpdates      bool
+	deleteDuplicateMCs      bool
+	fleetHealthPeriod       time.Duration
+	syncerChannelImages     map[string]string
+	provisionedCondition    bool
+	importCommandTemplate   string
+	importCommandCLI        string
+	importCommandSleep      time.Duration
+	hubConfigPollPeriod     time.Duration
+	syncerTokenExpiration   time.Duration
+	clusterDeletionRequeue  time.Duration
+	workDeletionRequeue     time.Duration
+	hubHealthPeriod         time.Duration
+	deleteOrphanMCs         bool
+	orphanSweepPeriod       time.Duration
+	reconcileTimeout        time.Duration
 }
 
 func init() {
@@ -75,6 +110,74 @@ func NewManager() *cobra.Command {
 	cmd.Flags().BoolVar(&o.enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	cmd.Flags().DurationVar(&o.leaseDuration, "leader-election-lease-duration", helpers.DefaultLeaderElectionLeaseDuration,
+		"The time the non-leader candidates wait before acquiring the leader election lease.")
+	cmd.Flags().DurationVar(&o.renewDeadline, "leader-election-renew-deadline", helpers.DefaultLeaderElectionRenewDeadline,
+		"The time the leader retries renewing the leader election lease before giving it up, shorter than the lease duration.")
+	cmd.Flags().StringVar(&o.importSecretNamespace, "import-secret-namespace", "",
+		"The compute namespace where the import secrets are created. "+
+			"Defaults to the namespace of the RegisteredCluster.")
+	cmd.Flags().StringToStringVar(&o.importSecretLabels, "import-secret-labels", map[string]string{},
+		"The labels added to the import secrets, for example key1=value1,key2=value2.")
+	cmd.Flags().BoolVar(&o.syncerFailureEvents, "syncer-failure-events", false,
+		"Emit a warning event on the RegisteredCluster when the kcp-syncer deployment is unavailable.")
+	cmd.Flags().StringSliceVar(&o.syncerEvictionTaints, "syncer-eviction-taints", []string{},
+		"The ManagedCluster taint keys which remove the kcp-syncer from the cluster, for example cluster.open-cluster-management.io/unreachable.")
+	cmd.Flags().DurationVar(&o.gracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second,
+		"The duration given to in-flight reconciles to complete before the manager stops.")
+	cmd.Flags().StringVar(&o.pprofAddr, "pprof-bind-address", "",
+		"The address the pprof endpoint binds to. The endpoint is disabled if empty.")
+	cmd.Flags().DurationVar(&o.requeueMaxDelay, "requeue-max-delay", 5*time.Minute,
+		"The maximum delay of the exponential backoff of the requeues.")
+	cmd.Flags().Float64Var(&o.requeueJitter, "requeue-jitter", 0.1,
+		"The maximum factor of the requeue delay randomly added to spread the requeues.")
+	cmd.Flags().BoolVar(&o.requireWorkspace, "require-workspace", true,
+		"Fail the compute client calls made without a kcp workspace instead of targeting the root workspace.")
+	cmd.Flags().BoolVar(&o.deleteClusterSetBinding, "delete-clusterset-bindings", false,
+		"Delete the ManagedClusterSetBindings of a workspace when its last RegisteredCluster is deleted.")
+	cmd.Flags().BoolVar(&o.propagateConsoleURL, "propagate-console-url", false,
+		"Copy the console URL of the hub ManagedClusterInfo in the RegisteredCluster status.")
+	cmd.Flags().IntVar(&o.importApplyRetries, "import-apply-retries", 3,
+		"The number of retries of the import secret apply on a transient error, 0 disables the retries.")
+	cmd.Flags().DurationVar(&o.importSecretTimeout, "import-secret-timeout", 10*time.Minute,
+		"The time after the ManagedCluster creation after which a missing import secret is reported "+
+			"in the ImportSecretGenerated condition, 0 disables the condition.")
+	cmd.Flags().BoolVar(&o.batchStatusUpdates, "batch-status-updates", true,
+		"Write the RegisteredCluster status changes of a reconcile with a single patch at the end of the reconcile, "+
+			"false writes each change with its own patch.")
+	cmd.Flags().BoolVar(&o.deleteDuplicateMCs, "delete-duplicate-managedclusters", false,
+		"Delete the not joined duplicate ManagedClusters of a RegisteredCluster, the oldest ManagedCluster is kept.")
+	cmd.Flags().DurationVar(&o.fleetHealthPeriod, "fleet-health-period", time.Minute,
+		"The period of the fleet health metrics computed from all RegisteredClusters, 0 disables the metrics.")
+	cmd.Flags().StringToStringVar(&o.syncerChannelImages, "syncer-channel-images", map[string]string{},
+		"The kcp-syncer images of the syncer channels selected by the "+SyncerChannelAnnotation+
+			" annotation, for example canary=image1,stable=image2.")
+	cmd.Flags().BoolVar(&o.provisionedCondition, "provisioned-condition", true,
+		"Report the provisioning in the RegisteredCluster Provisioned condition, Pending from the first reconcile.")
+	cmd.Flags().StringVar(&o.importCommandTemplate, "import-command-template", helpers.DefaultImportCommandTemplate,
+		"The Go template of the import command, rendered with the base64 encoded .CRDs and .Import, the .CLI command line "+
+			"and the .Sleep seconds between the applies. The quote function shell quotes a value.")
+	cmd.Flags().StringVar(&o.importCommandCLI, "import-command-cli", helpers.DefaultImportCommandCLI,
+		"The command line applying the import resources in the import command, for example kubectl or oc.")
+	cmd.Flags().DurationVar(&o.importCommandSleep, "import-command-sleep", helpers.DefaultImportCommandSleep,
+		"The pause of the import command between the crds and the import resources applies, rendered as .Sleep seconds.")
+	cmd.Flags().DurationVar(&o.hubConfigPollPeriod, "hubconfig-poll-period", 30*time.Second,
+		"The period at which the HubConfigs created after the start are looked for and their hubs added, 0 disables it.")
+	cmd.Flags().DurationVar(&o.syncerTokenExpiration, "syncer-token-expiration", helpers.DefaultSyncerTokenExpiration,
+		"The expiration of the bound tokens minted for the kcp-syncer when its ServiceAccount has no legacy token secret, "+
+			"the tokens are renewed when 80% of it is elapsed.")
+	cmd.Flags().DurationVar(&o.clusterDeletionRequeue, "managedcluster-deletion-requeue", defaultClusterDeletionRequeue,
+		"The delay before the deletion of the ManagedCluster of a deleted RegisteredCluster is checked again.")
+	cmd.Flags().DurationVar(&o.workDeletionRequeue, "manifestwork-deletion-requeue", defaultWorkDeletionRequeue,
+		"The delay before the deletion of the kcp-syncer manifestworks is checked again.")
+	cmd.Flags().DurationVar(&o.hubHealthPeriod, "hub-health-period", time.Minute,
+		"The period at which the hub APIs are checked and the HubReachable condition of the HubConfigs updated, 0 disables it.")
+	cmd.Flags().BoolVar(&o.deleteOrphanMCs, "delete-orphan-managedclusters", false,
+		"Periodically delete the ManagedClusters whose RegisteredCluster no longer exists, for example after its finalizer was removed manually.")
+	cmd.Flags().DurationVar(&o.orphanSweepPeriod, "orphan-managedclusters-sweep-period", 10*time.Minute,
+		"The period at which the orphaned ManagedClusters are looked for when --delete-orphan-managedclusters is set.")
+	cmd.Flags().DurationVar(&o.reconcileTimeout, "reconcile-timeout", defaultReconcileTimeout,
+		"The deadline of the compute and hub API calls of a RegisteredCluster reconcile, the reconcile fails and is requeued when it is exceeded.")
 	return cmd
 }
 
@@ -89,6 +192,30 @@ func (o *managerOptions) run() {
 
 	setupLog.Info("Setup Manager")
 
+	importCommandTemplate, err := helpers.ParseImportCommandTemplate(o.importCommandTemplate)
+	if err != nil {
+		setupLog.Error(giterrors.WithStack(err), "invalid import command template")
+		os.Exit(1)
+	}
+
+	if o.reconcileTimeout <= 0 {
+		setupLog.Error(fmt.Errorf("the reconcile timeout %s must be positive", o.reconcileTimeout), "invalid reconcile timeout")
+		os.Exit(1)
+	}
+
+	if o.enableLeaderElection {
+		if err := helpers.ValidateLeaderElectionDurations(o.leaseDuration, o.renewDeadline); err != nil {
+			setupLog.Error(err, "invalid leader election configuration")
+			os.Exit(1)
+		}
+	}
+
+	if o.syncerTokenExpiration < helpers.MinSyncerTokenExpiration {
+		setupLog.Error(fmt.Errorf("the syncer token expiration %s is less than %s", o.syncerTokenExpiration, helpers.MinSyncerTokenExpiration),
+			"invalid syncer token expiration")
+		os.Exit(1)
+	}
+
 	// controller cluster clients
 	kubeClient := kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie())
 	dynamicClient := dynamic.NewForConfigOrDie(ctrl.GetConfigOrDie())
@@ -153,6 +280,10 @@ func (o *managerOptions) run() {
 		// The leader must be created on the compute-operator cluster and not on the compute service
 		LeaderElectionConfig: ctrl.GetConfigOrDie(),
 		LeaderElectionID:     "628f2987.cluster-registration.io",
+		LeaseDuration:        &o.leaseDuration,
+		RenewDeadline:        &o.renewDeadline,
+		// Let in-flight reconciles complete to avoid partially applied resources on rollout
+		GracefulShutdownTimeout: &o.gracefulShutdownTimeout,
 		// NewCache:             helpers.NewClusterAwareCacheFunc,
 	}
 
@@ -168,6 +299,10 @@ func (o *managerOptions) run() {
 	}
 
 	computeKubeconfig = apimachineryclient.NewClusterConfig(cfg)
+	if o.requireWorkspace {
+		computeKubeconfig = helpers.NewRequireWorkspaceConfig(computeKubeconfig)
+		opts.NewClient = newRequireWorkspaceClient
+	}
 
 	computeKubeClient, err := kubernetes.NewForConfig(computeKubeconfig)
 	if err != nil {
@@ -194,6 +329,13 @@ func (o *managerOptions) run() {
 
 	setupLog.Info("server url:", "cfg.Host", cfg.Host)
 
+	// The uncached reader of the manager is not cluster aware, this one reads in the workspace of the context
+	computeAPIReader, err := client.New(computeKubeconfig, client.Options{Scheme: scheme, Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		setupLog.Error(giterrors.WithStack(err), "error creating the uncached client for virtual workspace URL")
+		os.Exit(1)
+	}
+
 	// add healthz/readyz check handler
 	setupLog.Info("Add health check")
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -207,6 +349,14 @@ func (o *managerOptions) run() {
 		os.Exit(1)
 	}
 
+	if len(o.pprofAddr) != 0 {
+		setupLog.Info("Add pprof endpoint", "address", o.pprofAddr)
+		if err := mgr.Add(&helpers.PprofServer{Addr: o.pprofAddr}); err != nil {
+			setupLog.Error(giterrors.WithStack(err), "unable to add pprof endpoint")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("Add RegisteredCluster reconciler")
 
 	hubInstances, err := helpers.GetHubClusters(context.Background(), mgr, kubeClient, dynamicClient)
@@ -214,20 +364,150 @@ func (o *managerOptions) run() {
 		setupLog.Error(giterrors.WithStack(err), "unable to retreive the hubCluster", "controller", "Cluster Registration")
 		os.Exit(1)
 	}
-	if err = (&RegisteredClusterReconciler{
+
+	// Summarize the effective configuration for support, the kubeconfigs and tokens are not logged
+	hubNames := make([]string, 0, len(hubInstances))
+	for _, hubInstance := range hubInstances {
+		hubNames = append(hubNames, hubInstance.HubConfig.Name)
+	}
+	setupLog.Info("Effective configuration",
+		"controllerNamespace", podNamespace,
+		"computeServer", computeKubeconfig.Host,
+		"syncerImage", getSyncerImage(),
+		"hubCount", len(hubInstances),
+		"hubs", hubNames,
+		"metricsAddr", o.metricsAddr,
+		"probeAddr", o.probeAddr,
+		"enableLeaderElection", o.enableLeaderElection,
+		"leaderElectionLeaseDuration", o.leaseDuration,
+		"leaderElectionRenewDeadline", o.renewDeadline,
+		"importSecretNamespace", o.importSecretNamespace,
+		"importSecretLabels", o.importSecretLabels,
+		"syncerFailureEvents", o.syncerFailureEvents,
+		"syncerEvictionTaints", o.syncerEvictionTaints,
+		"gracefulShutdownTimeout", o.gracefulShutdownTimeout,
+		"pprofAddr", o.pprofAddr,
+		"requeueMaxDelay", o.requeueMaxDelay,
+		"requeueJitter", o.requeueJitter,
+		"requireWorkspace", o.requireWorkspace,
+		"deleteClusterSetBindings", o.deleteClusterSetBinding,
+		"propagateConsoleURL", o.propagateConsoleURL,
+		"importApplyRetries", o.importApplyRetries,
+		"importSecretTimeout", o.importSecretTimeout,
+		"batchStatusUpdates", o.batchStatusUpdates,
+		"deleteDuplicateManagedClusters", o.deleteDuplicateMCs,
+		"fleetHealthPeriod", o.fleetHealthPeriod,
+		"syncerChannelImages", o.syncerChannelImages,
+		"provisionedCondition", o.provisionedCondition,
+		"importCommandTemplate", o.importCommandTemplate,
+		"importCommandCLI", o.importCommandCLI,
+		"importCommandSleep", o.importCommandSleep,
+		"hubConfigPollPeriod", o.hubConfigPollPeriod,
+		"syncerTokenExpiration", o.syncerTokenExpiration,
+		"managedClusterDeletionRequeue", o.clusterDeletionRequeue,
+		"manifestWorkDeletionRequeue", o.workDeletionRequeue,
+		"hubHealthPeriod", o.hubHealthPeriod,
+		"deleteOrphanManagedClusters", o.deleteOrphanMCs,
+		"orphanManagedClustersSweepPeriod", o.orphanSweepPeriod,
+		"reconcileTimeout", o.reconcileTimeout)
+	registeredClusterReconciler := &RegisteredClusterReconciler{
 		Client:                    mgr.GetClient(),
 		Log:                       ctrl.Log.WithName("controllers").WithName("RegisteredCluster"),
 		Scheme:                    scheme,
+		Recorder:                  mgr.GetEventRecorderFor("compute-operator"),
 		HubClusters:               hubInstances,
 		ComputeConfig:             cfg,
+		KubeClient:                kubeClient,
 		ComputeKubeClient:         computeKubeClient,
 		ComputeDynamicClient:      computeDynamicClient,
 		ComputeAPIExtensionClient: computeApiExtensionClient,
-	}).SetupWithManager(mgr, scheme); err != nil {
+		ImportSecretNamespace:     o.importSecretNamespace,
+		ImportSecretLabels:        o.importSecretLabels,
+		SyncerFailureEvents:       o.syncerFailureEvents,
+		SyncerEvictionTaints:      o.syncerEvictionTaints,
+		DeleteClusterSetBindings:  o.deleteClusterSetBinding,
+		PropagateConsoleURL:       o.propagateConsoleURL,
+		ImportApplyRetries:        o.importApplyRetries,
+		ImportSecretTimeout:       o.importSecretTimeout,
+		BatchStatusUpdates:        o.batchStatusUpdates,
+		DeleteDuplicateClusters:   o.deleteDuplicateMCs,
+		SyncerChannelImages:       o.syncerChannelImages,
+		ProvisionedCondition:      o.provisionedCondition,
+		ImportCommandTemplate:     importCommandTemplate,
+		ImportCommandCLI:          o.importCommandCLI,
+		ImportCommandSleep:        o.importCommandSleep,
+		SyncerTokenExpiration:     o.syncerTokenExpiration,
+		ClusterDeletionRequeue:    o.clusterDeletionRequeue,
+		WorkDeletionRequeue:       o.workDeletionRequeue,
+		ReconcileTimeout:          o.reconcileTimeout,
+		Backoff: &helpers.RequeueBackoff{
+			MaxDelay: o.requeueMaxDelay,
+			Jitter:   o.requeueJitter,
+		},
+	}
+	if err = registeredClusterReconciler.SetupWithManager(mgr, scheme); err != nil {
 		setupLog.Error(giterrors.WithStack(err), "unable to create controller", "controller", "Cluster Registration")
 		os.Exit(1)
 	}
 
+	if o.hubConfigPollPeriod > 0 {
+		setupLog.Info("Add HubConfig watcher", "period", o.hubConfigPollPeriod)
+		if err := mgr.Add(&HubConfigWatcher{
+			Manager:       mgr,
+			KubeClient:    kubeClient,
+			DynamicClient: dynamicClient,
+			Reconciler:    registeredClusterReconciler,
+			Log:           ctrl.Log.WithName("controllers").WithName("HubConfigWatcher"),
+			Period:        o.hubConfigPollPeriod,
+		}); err != nil {
+			setupLog.Error(giterrors.WithStack(err), "unable to add HubConfig watcher")
+			os.Exit(1)
+		}
+	}
+
+	if o.hubHealthPeriod > 0 {
+		setupLog.Info("Add hub health checker", "period", o.hubHealthPeriod)
+		if err := mgr.Add(&HubHealthChecker{
+			Manager:       mgr,
+			KubeClient:    kubeClient,
+			DynamicClient: dynamicClient,
+			Reconciler:    registeredClusterReconciler,
+			Log:           ctrl.Log.WithName("controllers").WithName("HubHealthChecker"),
+			Period:        o.hubHealthPeriod,
+		}); err != nil {
+			setupLog.Error(giterrors.WithStack(err), "unable to add hub health checker")
+			os.Exit(1)
+		}
+	}
+
+	if o.deleteOrphanMCs && o.orphanSweepPeriod > 0 {
+		setupLog.Info("Add orphaned ManagedClusters sweeper", "period", o.orphanSweepPeriod)
+		if err := mgr.Add(&OrphanSweeper{
+			Client:     mgr.GetClient(),
+			APIReader:  computeAPIReader,
+			Cache:      mgr.GetCache(),
+			Reconciler: registeredClusterReconciler,
+			Log:        ctrl.Log.WithName("controllers").WithName("OrphanSweeper"),
+			Period:     o.orphanSweepPeriod,
+		}); err != nil {
+			setupLog.Error(giterrors.WithStack(err), "unable to add orphaned ManagedClusters sweeper")
+			os.Exit(1)
+		}
+	}
+
+	if o.fleetHealthPeriod > 0 {
+		setupLog.Info("Add fleet health reporter", "period", o.fleetHealthPeriod)
+		if err := mgr.Add(&FleetHealthReporter{
+			Client:      mgr.GetClient(),
+			Log:         ctrl.Log.WithName("controllers").WithName("FleetHealth"),
+			HubClusters: registeredClusterReconciler.getHubClusters,
+			Period:      o.fleetHealthPeriod,
+		}); err != nil {
+			setupLog.Error(giterrors.WithStack(err), "unable to add fleet health reporter")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("Starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(giterrors.WithStack(err), "problem running manager")
@@ -235,3 +515,11 @@ func (o *managerOptions) run() {
 	}
 
 }
+
+// newRequireWorkspaceClient returns the cluster aware client of the manager, whose API calls fail without a kcp
+// workspace in the context, as the Compute clients with --require-workspace. The cached reads are served by the
+// cache. The cache and the RESTMapper of the manager are exempted, they list and discover the resources of all the
+// workspaces with the /clusters/* path and never have a workspace in their context.
+func newRequireWorkspaceClient(cache cache.Cache, config *rest.Config, opts client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+	return kcp.NewClusterAwareClient(cache, helpers.NewRequireWorkspaceConfig(config), opts, uncachedObjects...)
+}
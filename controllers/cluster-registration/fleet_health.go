@@ -0,0 +1,77 @@
+// Copyright Red Hat
+
+package registeredcluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/pkg/helpers"
+)
+
+// fleetHealthGauge is the number of RegisteredClusters of all workspaces per hub and fleet health state.
+var fleetHealthGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "compute_operator_registeredclusters",
+		Help: "Number of RegisteredClusters of all workspaces per hub and state (importing, healthy, degraded).",
+	},
+	[]string{"hub", "state"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(fleetHealthGauge)
+}
+
+// FleetHealthReporter is a manager runnable periodically computing the fleet health metrics
+// from the RegisteredClusters of all workspaces. HubClusters returns the current hubs, as hubs can be
+// added while the operator runs.
+type FleetHealthReporter struct {
+	Client      client.Client
+	Log         logr.Logger
+	HubClusters func() []helpers.HubInstance
+	Period      time.Duration
+}
+
+// Start reports the fleet health every period until the context is done.
+func (f *FleetHealthReporter) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, f.report, f.Period)
+	return nil
+}
+
+func (f *FleetHealthReporter) report(ctx context.Context) {
+	// The context has no workspace, the cached list returns the RegisteredClusters of all workspaces
+	regClusters := &singaporev1alpha1.RegisteredClusterList{}
+	if err := f.Client.List(ctx, regClusters); err != nil {
+		f.Log.Error(err, "failed to list the registeredclusters")
+		return
+	}
+
+	hubClusters := f.HubClusters()
+	counts := map[string]map[string]int{}
+	for _, hubCluster := range hubClusters {
+		counts[hubCluster.HubConfig.Name] = map[string]int{}
+	}
+	for i := range regClusters.Items {
+		regCluster := &regClusters.Items[i]
+		hubCluster, err := helpers.GetHubCluster(regCluster.Namespace, regCluster.GetAnnotations(), hubClusters)
+		if err != nil {
+			f.Log.Error(err, "failed to get the hub of the registeredcluster", "namespace", regCluster.Namespace, "name", regCluster.Name)
+			continue
+		}
+		counts[hubCluster.HubConfig.Name][helpers.GetFleetHealthState(regCluster.Status.Conditions)]++
+	}
+
+	for hubName, hubCounts := range counts {
+		for _, state := range helpers.FleetHealthStates {
+			fleetHealthGauge.WithLabelValues(hubName, state).Set(float64(hubCounts[state]))
+		}
+	}
+	f.Log.V(1).Info("fleet health reported", "registeredclusters", len(regClusters.Items), "counts", counts)
+}
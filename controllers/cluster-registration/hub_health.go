@@ -0,0 +1,113 @@
+// Copyright Red Hat
+
+package registeredcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/pkg/helpers"
+)
+
+// HubHealthChecker is a manager runnable periodically checking the API of the hubs of the RegisteredCluster
+// reconciler. The result is reported in the HubReachable condition of the HubConfigs. When the health of a hub
+// changes, its RegisteredClusters are reconciled again. A hub whose credentials are rejected is rebuilt from
+// its kubeconfig secret once the secret holds valid credentials.
+type HubHealthChecker struct {
+	Manager       ctrl.Manager
+	KubeClient    kubernetes.Interface
+	DynamicClient dynamic.Interface
+	Reconciler    *RegisteredClusterReconciler
+	Log           logr.Logger
+	Period        time.Duration
+
+	// healthy is the result of the last check per HubConfig name
+	healthy map[string]bool
+}
+
+// Start checks the hubs every period until the context is done.
+func (c *HubHealthChecker) Start(ctx context.Context) error {
+	c.healthy = map[string]bool{}
+	wait.UntilWithContext(ctx, c.checkHubClusters, c.Period)
+	return nil
+}
+
+func (c *HubHealthChecker) checkHubClusters(ctx context.Context) {
+	for _, hubCluster := range c.Reconciler.getHubClusters() {
+		hubName := hubCluster.HubConfig.Name
+		condition := c.checkHubCluster(ctx, hubCluster)
+		if err := helpers.SetHubConfigCondition(ctx, c.DynamicClient, hubCluster.HubConfig, condition); err != nil {
+			c.Log.Error(err, "failed to update the hubconfig condition", "hubConfig.Name", hubName)
+		}
+
+		healthy := condition.Status == metav1.ConditionTrue
+		if previous, ok := c.healthy[hubName]; ok && previous != healthy {
+			c.Log.Info("hub health changed, requeue its registeredclusters", "hubConfig.Name", hubName, "healthy", healthy)
+			if err := c.Reconciler.requeueHubRegisteredClusters(ctx, hubName); err != nil {
+				c.Log.Error(err, "failed to requeue the registeredclusters of the hub", "hubConfig.Name", hubName)
+			}
+		}
+		c.healthy[hubName] = healthy
+	}
+}
+
+// checkHubCluster pings the hub and returns its HubReachable condition. A hub rejecting its credentials is
+// replaced in the reconciler when its kubeconfig secret holds valid credentials.
+func (c *HubHealthChecker) checkHubCluster(ctx context.Context, hubCluster helpers.HubInstance) metav1.Condition {
+	hubName := hubCluster.HubConfig.Name
+	err := hubCluster.Ping(ctx)
+	switch {
+	case err == nil:
+		return metav1.Condition{
+			Type:    singaporev1alpha1.HubConfigConditionReachable,
+			Status:  metav1.ConditionTrue,
+			Reason:  "HubReachable",
+			Message: "The hub is reachable",
+		}
+	case k8serrors.IsUnauthorized(err):
+		reloadedHubCluster, reloadErr := helpers.ReloadHubCluster(ctx, c.Manager, c.KubeClient, c.DynamicClient, hubCluster)
+		if reloadErr != nil {
+			c.Log.V(1).Info("hub credentials rejected and not yet renewed", "hubConfig.Name", hubName, "error", reloadErr.Error())
+			return metav1.Condition{
+				Type:   singaporev1alpha1.HubConfigConditionReachable,
+				Status: metav1.ConditionFalse,
+				Reason: "HubUnauthorized",
+				Message: fmt.Sprintf("The hub rejects the credentials of the kubeconfig secret %s, the kubeconfig may be expired: %s",
+					hubCluster.HubConfig.Spec.KubeConfigSecretRef.Name, err.Error()),
+			}
+		}
+		if err := c.Reconciler.replaceHubCluster(*reloadedHubCluster); err != nil {
+			c.Log.Error(err, "failed to replace the hub", "hubConfig.Name", hubName)
+			return metav1.Condition{
+				Type:    singaporev1alpha1.HubConfigConditionReachable,
+				Status:  metav1.ConditionFalse,
+				Reason:  "HubReloadFailed",
+				Message: fmt.Sprintf("The hub could not be reloaded with the renewed credentials: %s", err.Error()),
+			}
+		}
+		c.Log.Info("hub reloaded with renewed credentials", "hubConfig.Name", hubName)
+		return metav1.Condition{
+			Type:    singaporev1alpha1.HubConfigConditionReachable,
+			Status:  metav1.ConditionTrue,
+			Reason:  "HubReloaded",
+			Message: "The hub is reachable with the renewed credentials of the kubeconfig secret",
+		}
+	default:
+		return metav1.Condition{
+			Type:    singaporev1alpha1.HubConfigConditionReachable,
+			Status:  metav1.ConditionFalse,
+			Reason:  "HubUnreachable",
+			Message: fmt.Sprintf("The hub is unreachable: %s", err.Error()),
+		}
+	}
+}
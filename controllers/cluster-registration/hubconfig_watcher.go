@@ -0,0 +1,49 @@
+// Copyright Red Hat
+
+package registeredcluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/compute-operator/pkg/helpers"
+)
+
+// HubConfigWatcher is a manager runnable periodically adding the hubs of the HubConfigs created
+// after the operator start to the RegisteredCluster reconciler, so a new HubConfig does not require
+// a restart of the operator.
+type HubConfigWatcher struct {
+	Manager       ctrl.Manager
+	KubeClient    kubernetes.Interface
+	DynamicClient dynamic.Interface
+	Reconciler    *RegisteredClusterReconciler
+	Log           logr.Logger
+	Period        time.Duration
+}
+
+// Start looks for new HubConfigs every period until the context is done.
+func (w *HubConfigWatcher) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, w.addHubClusters, w.Period)
+	return nil
+}
+
+func (w *HubConfigWatcher) addHubClusters(ctx context.Context) {
+	hubInstances, err := helpers.GetNewHubClusters(ctx, w.Manager, w.KubeClient, w.DynamicClient, w.Reconciler.getHubClusters())
+	if err != nil {
+		w.Log.Error(err, "failed to get the new hubconfigs")
+		return
+	}
+	for _, hubInstance := range hubInstances {
+		if err := w.Reconciler.addHubCluster(hubInstance); err != nil {
+			w.Log.Error(err, "failed to add the hub", "hubConfig.Name", hubInstance.HubConfig.Name)
+			continue
+		}
+		w.Log.Info("hub added", "hubConfig.Name", hubInstance.HubConfig.Name)
+	}
+}
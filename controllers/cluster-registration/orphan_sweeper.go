@@ -0,0 +1,114 @@
+// Copyright Red Hat
+
+package registeredcluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kcp-dev/logicalcluster/v2"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/pkg/helpers"
+)
+
+// OrphanSweeper is a manager runnable periodically deleting the ManagedClusters of the hubs whose RegisteredCluster
+// no longer exists, for example when the finalizer of the RegisteredCluster was removed manually. Only the
+// ManagedClusters created for the compute service and older than the period are deleted, so a RegisteredCluster
+// not yet in the cache doesn't lose its ManagedCluster. Before a deletion, the RegisteredCluster is read again
+// from its workspace without the cache, so a stale cache never deletes a ManagedCluster in use.
+type OrphanSweeper struct {
+	Client     client.Client
+	APIReader  client.Reader
+	Cache      cache.Cache
+	Reconciler *RegisteredClusterReconciler
+	Log        logr.Logger
+	Period     time.Duration
+}
+
+// Start sweeps the orphaned ManagedClusters every period until the context is done.
+func (s *OrphanSweeper) Start(ctx context.Context) error {
+	if !s.Cache.WaitForCacheSync(ctx) {
+		return nil
+	}
+	wait.UntilWithContext(ctx, s.sweep, s.Period)
+	return nil
+}
+
+func (s *OrphanSweeper) sweep(ctx context.Context) {
+	// The context has no workspace, the cached list returns the RegisteredClusters of all workspaces
+	regClusters := &singaporev1alpha1.RegisteredClusterList{}
+	if err := s.Client.List(ctx, regClusters); err != nil {
+		s.Log.Error(err, "failed to list the registeredclusters")
+		return
+	}
+	uids := make(map[string]bool, len(regClusters.Items))
+	for i := range regClusters.Items {
+		uids[string(regClusters.Items[i].UID)] = true
+	}
+
+	for _, hubCluster := range s.Reconciler.getHubClusters() {
+		managedClusters := &clusterapiv1.ManagedClusterList{}
+		if err := hubCluster.Client.List(ctx, managedClusters, client.HasLabels{RegisteredClusterUidLabel}); err != nil {
+			s.Log.Error(err, "failed to list the managedclusters", "hub", hubCluster.HubConfig.Name)
+			continue
+		}
+		for i := range managedClusters.Items {
+			managedCluster := &managedClusters.Items[i]
+			uid := managedCluster.GetLabels()[RegisteredClusterUidLabel]
+			if uids[uid] || managedCluster.DeletionTimestamp != nil ||
+				managedCluster.GetAnnotations()["open-cluster-management/service-name"] != "compute" ||
+				time.Since(managedCluster.CreationTimestamp.Time) < s.Period {
+				continue
+			}
+			if !s.isOrphaned(ctx, managedCluster) {
+				continue
+			}
+			s.Log.Info("delete orphaned managedcluster, its registeredcluster no longer exists",
+				"hub", hubCluster.HubConfig.Name,
+				"managedcluster", managedCluster.Name,
+				"registeredcluster namespace", managedCluster.GetLabels()[RegisteredClusterNamespacelabel],
+				"registeredcluster name", managedCluster.GetLabels()[RegisteredClusterNamelabel],
+				"registeredcluster uid", uid,
+				"workspace", managedCluster.GetAnnotations()[ClusterNameAnnotation])
+			if err := hubCluster.Client.Delete(ctx, managedCluster); err != nil && !k8serrors.IsNotFound(err) {
+				s.Log.Error(err, "failed to delete the orphaned managedcluster",
+					"hub", hubCluster.HubConfig.Name,
+					"managedcluster", managedCluster.Name)
+			}
+		}
+	}
+}
+
+// isOrphaned reads the RegisteredCluster of the ManagedCluster in its workspace without the cache, and returns true
+// only if it doesn't exist or has another uid. Any other outcome keeps the ManagedCluster.
+func (s *OrphanSweeper) isOrphaned(ctx context.Context, managedCluster *clusterapiv1.ManagedCluster) bool {
+	labels := managedCluster.GetLabels()
+	workspace := managedCluster.GetAnnotations()[ClusterNameAnnotation]
+	if len(workspace) == 0 {
+		s.Log.V(1).Info("skip managedcluster without workspace annotation", "managedcluster", managedCluster.Name)
+		return false
+	}
+	regCluster := &singaporev1alpha1.RegisteredCluster{}
+	err := s.APIReader.Get(logicalcluster.WithCluster(ctx, logicalcluster.New(workspace)),
+		types.NamespacedName{Namespace: labels[RegisteredClusterNamespacelabel], Name: labels[RegisteredClusterNamelabel]},
+		regCluster)
+	switch {
+	case err == nil:
+		return string(regCluster.UID) != labels[RegisteredClusterUidLabel]
+	case k8serrors.IsNotFound(err) && !helpers.IsResourceNotFound(err):
+		return true
+	default:
+		// The workspace may be unbound or unreachable, the RegisteredCluster can't be proven gone
+		s.Log.V(1).Info("skip managedcluster, failed to read its registeredcluster",
+			"managedcluster", managedCluster.Name, "workspace", workspace, "error", err.Error())
+		return false
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright Red Hat
+
+package registeredcluster
+
+import (
+	"context"
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	giterrors "github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/pkg/helpers"
+)
+
+// statusBatch accumulates the status changes of a reconcile, so they are written with a single patch
+// at the end of the reconcile instead of one patch per phase.
+type statusBatch struct {
+	// fields are the top level status fields, a later change of a field replaces the earlier one
+	fields map[string]interface{}
+	// conditions are merged in the RegisteredCluster conditions when the batch is flushed
+	conditions []metav1.Condition
+}
+
+type statusBatchKey struct{}
+
+// withStatusBatch returns a copy of the context carrying a new status batch. The status patches done
+// with the returned context are accumulated in the batch until it is flushed.
+func withStatusBatch(ctx context.Context) (context.Context, *statusBatch) {
+	batch := &statusBatch{fields: map[string]interface{}{}}
+	return context.WithValue(ctx, statusBatchKey{}, batch), batch
+}
+
+// statusBatchFrom returns the status batch of the context, nil if the status patches are not batched.
+func statusBatchFrom(ctx context.Context) *statusBatch {
+	batch, _ := ctx.Value(statusBatchKey{}).(*statusBatch)
+	return batch
+}
+
+// addFields accumulates the top level status fields in the batch and applies them to the status of the
+// RegisteredCluster, so the rest of the reconcile sees the status it will write.
+func (b *statusBatch) addFields(regCluster *singaporev1alpha1.RegisteredCluster, fields map[string]interface{}) error {
+	current, err := json.Marshal(regCluster.Status)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	patch, err := json.Marshal(fields)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	merged, err := jsonpatch.MergePatch(current, patch)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	status := singaporev1alpha1.RegisteredClusterStatus{}
+	if err := json.Unmarshal(merged, &status); err != nil {
+		return giterrors.WithStack(err)
+	}
+	regCluster.Status = status
+	for field, value := range fields {
+		b.fields[field] = value
+	}
+	return nil
+}
+
+func (b *statusBatch) isEmpty() bool {
+	return len(b.fields) == 0 && len(b.conditions) == 0
+}
+
+// flushStatus writes the status changes accumulated in the batch with a single merge patch. The patch is done
+// with an optimistic lock when it contains conditions, as a merge patch replaces the whole conditions list.
+func (r *RegisteredClusterReconciler) flushStatus(computeCtx context.Context, regCluster *singaporev1alpha1.RegisteredCluster, batch *statusBatch) error {
+	if batch.isEmpty() {
+		return nil
+	}
+	status := map[string]interface{}{}
+	for field, value := range batch.fields {
+		status[field] = value
+	}
+	patch := map[string]interface{}{"status": status}
+	if len(batch.conditions) != 0 {
+		status["conditions"] = helpers.MergeStatusConditions(regCluster.Status.Conditions, batch.conditions...)
+		patch["metadata"] = map[string]interface{}{"resourceVersion": regCluster.ResourceVersion}
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	r.Log.V(2).Info("flush registeredcluster status",
+		"namespace", regCluster.Namespace,
+		"name", regCluster.Name,
+		"patch", string(data))
+	// The RegisteredCluster is gone once its finalizer is removed
+	if err := r.Client.Status().Patch(computeCtx, regCluster, client.RawPatch(types.MergePatchType, data)); err != nil && !k8serrors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
+	batch.fields = map[string]interface{}{}
+	batch.conditions = nil
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright Red Hat
+
+package registeredcluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/pkg/helpers"
+)
+
+func newStatusBatchTestReconciler(t *testing.T, objs ...*singaporev1alpha1.RegisteredCluster) *RegisteredClusterReconciler {
+	testScheme := runtime.NewScheme()
+	if err := singaporev1alpha1.AddToScheme(testScheme); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(testScheme)
+	for _, obj := range objs {
+		builder = builder.WithObjects(obj)
+	}
+	return &RegisteredClusterReconciler{
+		Client: builder.Build(),
+		Log:    logr.Discard(),
+	}
+}
+
+func getStatusBatchTestRegisteredCluster() *singaporev1alpha1.RegisteredCluster {
+	return &singaporev1alpha1.RegisteredCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: "ns1",
+		},
+	}
+}
+
+func TestStatusBatchLaterFieldOverrides(t *testing.T) {
+	r := newStatusBatchTestReconciler(t, getStatusBatchTestRegisteredCluster())
+	ctx, batch := withStatusBatch(context.TODO())
+
+	regCluster := &singaporev1alpha1.RegisteredCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"}, regCluster); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := r.patchStatus(ctx, regCluster, map[string]interface{}{"syncerImage": "quay.io/kcp/syncer:v0.6.0"}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := r.patchStatus(ctx, regCluster, map[string]interface{}{"syncerImage": "quay.io/kcp/syncer:v0.6.1"}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if regCluster.Status.SyncerImage != "quay.io/kcp/syncer:v0.6.1" {
+		t.Fatalf(`Batched status not as expected. Expected quay.io/kcp/syncer:v0.6.1, actual %s`, regCluster.Status.SyncerImage)
+	}
+	if err := r.flushStatus(ctx, regCluster, batch); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !batch.isEmpty() {
+		t.Fatalf(`Expected the batch to be empty once flushed`)
+	}
+
+	flushed := &singaporev1alpha1.RegisteredCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"}, flushed); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if flushed.Status.SyncerImage != "quay.io/kcp/syncer:v0.6.1" {
+		t.Fatalf(`Flushed status not as expected. Expected quay.io/kcp/syncer:v0.6.1, actual %s`, flushed.Status.SyncerImage)
+	}
+}
+
+func TestStatusBatchConditionsMergedAfterUpdate(t *testing.T) {
+	r := newStatusBatchTestReconciler(t, getStatusBatchTestRegisteredCluster())
+	ctx, batch := withStatusBatch(context.TODO())
+
+	regCluster := &singaporev1alpha1.RegisteredCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"}, regCluster); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionInvalidLocation,
+		Status:  metav1.ConditionFalse,
+		Reason:  "LocationValid",
+		Message: "The location workspaces are resolved on the compute server",
+	}
+	if err := r.patchStatusConditions(ctx, regCluster, condition); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	// An update refreshes the RegisteredCluster with the stored status, without the batched condition
+	refreshed := &singaporev1alpha1.RegisteredCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"}, refreshed); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	refreshed.Finalizers = append(refreshed.Finalizers, "test-finalizer")
+	if err := r.Update(ctx, refreshed); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(refreshed.Status.Conditions) != 0 {
+		t.Fatalf(`Expected the refreshed status to have no condition, actual %v`, refreshed.Status.Conditions)
+	}
+
+	if err := r.flushStatus(ctx, refreshed, batch); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	flushed := &singaporev1alpha1.RegisteredCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"}, flushed); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if status, ok := helpers.GetConditionStatus(flushed.Status.Conditions, condition.Type); !ok || status != metav1.ConditionFalse {
+		t.Fatalf(`Flushed conditions not as expected. Expected %s %s, actual %v`, condition.Type, metav1.ConditionFalse, flushed.Status.Conditions)
+	}
+	if len(flushed.Finalizers) != 1 {
+		t.Fatalf(`Finalizers not as expected. Expected [test-finalizer], actual %v`, flushed.Finalizers)
+	}
+}
+
+func TestStatusBatchEmptyNoWrite(t *testing.T) {
+	r := newStatusBatchTestReconciler(t, getStatusBatchTestRegisteredCluster())
+	ctx, batch := withStatusBatch(context.TODO())
+
+	regCluster := &singaporev1alpha1.RegisteredCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"}, regCluster); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	resourceVersion := regCluster.ResourceVersion
+	if err := r.flushStatus(ctx, regCluster, batch); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	flushed := &singaporev1alpha1.RegisteredCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "cluster1"}, flushed); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if flushed.ResourceVersion != resourceVersion {
+		t.Fatalf(`Resource version not as expected. Expected %s, actual %s`, resourceVersion, flushed.ResourceVersion)
+	}
+}
+
+func TestStatusBatchNotFoundIgnored(t *testing.T) {
+	r := newStatusBatchTestReconciler(t)
+	ctx, batch := withStatusBatch(context.TODO())
+
+	// The RegisteredCluster is gone once its finalizer is removed
+	regCluster := getStatusBatchTestRegisteredCluster()
+	if err := r.patchStatus(ctx, regCluster, map[string]interface{}{"syncerImage": "quay.io/kcp/syncer:v0.6.1"}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := r.flushStatus(ctx, regCluster, batch); err != nil {
+		t.Fatalf("Expected the NotFound error to be ignored, actual %s", err)
+	}
+}
@@ -307,9 +307,9 @@ var _ = Describe("Process registeredCluster: ", func() {
 		}
 		// Define the expected import command
 		expectedImportCommand :=
-			`echo "bXktY3Jkc3YxLnlhbWw=" | base64 --decode | kubectl apply -f - && ` +
+			`echo 'bXktY3Jkc3YxLnlhbWw=' | base64 --decode | kubectl apply -f - && ` +
 				`sleep 2 && ` +
-				`echo "bXktaW1wb3J0LnlhbWw=" | base64 --decode | kubectl apply -f -`
+				`echo 'bXktaW1wb3J0LnlhbWw=' | base64 --decode | kubectl apply -f -`
 		// Create the fake import secret on the hub
 		By("Create import secret", func() {
 			err := controllerRuntimeClient.Create(context.TODO(), importSecret)
@@ -355,6 +355,12 @@ var _ = Describe("Process registeredCluster: ", func() {
 				if gotImportCommand != expectedImportCommand {
 					return fmt.Errorf("invalid import expect %s, got %s", expectedImportCommand, secret.Data["importCommand"])
 				}
+				if string(secret.Data["crds.yaml"]) != "my-crdsv1.yaml" || string(secret.Data["import.yaml"]) != "my-import.yaml" {
+					return fmt.Errorf("invalid import manifests, got %s and %s", secret.Data["crds.yaml"], secret.Data["import.yaml"])
+				}
+				if registeredCluster.Status.ImportManifestsRef != registeredCluster.Status.ImportCommandRef {
+					return fmt.Errorf("expect importManifestsRef %v, got %v", registeredCluster.Status.ImportCommandRef, registeredCluster.Status.ImportManifestsRef)
+				}
 				return nil
 			}, 30, 1).Should(BeNil())
 		})
@@ -431,8 +437,14 @@ var _ = Describe("Process registeredCluster: ", func() {
 			Eventually(func() error {
 				for _, locationWorkspace := range registeredCluster.Spec.Location {
 					locationContext := logicalcluster.WithCluster(computeContext, logicalcluster.New(locationWorkspace))
-					klog.Infof("getting service account %s in workspace %s", helpers.GetSyncerServiceAccountName(), locationWorkspace)
-					_, err := apiExportVirtualWorkspaceKubeClient.CoreV1().ServiceAccounts("default").Get(locationContext, helpers.GetSyncerServiceAccountName(), metav1.GetOptions{})
+					synctarget, err := getSyncTarget(locationContext, registeredCluster)
+					if err != nil {
+						klog.Info("SyncTarget Not found", "Error", err)
+						return err
+					}
+					saName := helpers.GetSyncerServiceAccountName(synctarget.GetName())
+					klog.Infof("getting service account %s in workspace %s", saName, locationWorkspace)
+					_, err = apiExportVirtualWorkspaceKubeClient.CoreV1().ServiceAccounts("default").Get(locationContext, saName, metav1.GetOptions{})
 					if err != nil {
 						klog.Errorf("failed getting service account %s", err)
 						return err
@@ -551,3 +563,132 @@ var _ = Describe("Process registeredCluster: ", func() {
 	})
 
 })
+
+var _ = Describe("Process registeredClusters of several workspaces: ", func() {
+	It("Process the registeredClusters independently", func() {
+		const (
+			multiWorkspaceNamespace         string = "rc-multi-ws"
+			multiWorkspaceRegisteredCluster string = "registered-cluster-multi-ws"
+		)
+		controllerRuntimeClient, err := client.New(controllerRestConfig, client.Options{Scheme: scheme})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(controllerRuntimeClient).ToNot(BeNil())
+
+		// The same RegisteredCluster name and namespace is used in both workspaces,
+		// only the workspace distinguishes them.
+		workspaces := []string{test.AbsoluteComputeWorkspace, test.AbsoluteComputeWorkspace2}
+		workspaceContexts := map[string]context.Context{}
+		for _, workspace := range workspaces {
+			workspaceContexts[workspace] = logicalcluster.WithCluster(context.Background(), logicalcluster.New(workspace))
+		}
+
+		for _, workspace := range workspaces {
+			workspaceContext := workspaceContexts[workspace]
+			By(fmt.Sprintf("Create the RegisteredCluster in workspace %s", workspace), func() {
+				Eventually(func() error {
+					ns := &corev1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: multiWorkspaceNamespace,
+						},
+					}
+					if err := test.ComputeAdminRuntimeClient.Create(workspaceContext, ns); err != nil && !errors.IsAlreadyExists(err) {
+						return err
+					}
+					registeredCluster := &singaporev1alpha1.RegisteredCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      multiWorkspaceRegisteredCluster,
+							Namespace: multiWorkspaceNamespace,
+						},
+						Spec: singaporev1alpha1.RegisteredClusterSpec{
+							Location: []string{test.AbsoluteLocationWorkspace1},
+						},
+					}
+					if err := test.ComputeAdminRuntimeClient.Create(workspaceContext, registeredCluster); err != nil && !errors.IsAlreadyExists(err) {
+						return err
+					}
+					return nil
+				}, 60, 3).Should(BeNil())
+			})
+		}
+
+		// Each RegisteredCluster must get its own managedcluster, annotated with its workspace
+		managedClusters := map[string]*clusterapiv1.ManagedCluster{}
+		By("Checking a managedCluster is created per workspace", func() {
+			Eventually(func() error {
+				managedClusterList := &clusterapiv1.ManagedClusterList{}
+				if err := controllerRuntimeClient.List(context.TODO(),
+					managedClusterList,
+					client.MatchingLabels{
+						RegisteredClusterNamelabel:      multiWorkspaceRegisteredCluster,
+						RegisteredClusterNamespacelabel: multiWorkspaceNamespace,
+					}); err != nil {
+					return err
+				}
+				if len(managedClusterList.Items) != len(workspaces) {
+					return fmt.Errorf("Number of managedCluster found %d", len(managedClusterList.Items))
+				}
+				for i := range managedClusterList.Items {
+					managedCluster := &managedClusterList.Items[i]
+					managedClusters[managedCluster.GetAnnotations()[ClusterNameAnnotation]] = managedCluster
+				}
+				for _, workspace := range workspaces {
+					if _, ok := managedClusters[workspace]; !ok {
+						return fmt.Errorf("no managedCluster found for workspace %s", workspace)
+					}
+				}
+				return nil
+			}, 60, 3).Should(BeNil())
+		})
+
+		// Set a different clusterID on each managedcluster, the hub events must be
+		// routed to the RegisteredCluster of the workspace owning the managedcluster.
+		for _, workspace := range workspaces {
+			managedCluster := managedClusters[workspace]
+			By(fmt.Sprintf("Updating managedcluster clusterID for workspace %s", workspace), func() {
+				managedCluster.ObjectMeta.Labels["clusterID"] = strings.ReplaceAll(workspace, ":", "-")
+				err := controllerRuntimeClient.Update(context.TODO(), managedCluster)
+				Expect(err).Should(BeNil())
+			})
+		}
+
+		for _, workspace := range workspaces {
+			workspaceContext := workspaceContexts[workspace]
+			By(fmt.Sprintf("Checking registeredCluster clusterID in workspace %s", workspace), func() {
+				Eventually(func() error {
+					registeredCluster := &singaporev1alpha1.RegisteredCluster{}
+					if err := test.ComputeAdminRuntimeClient.Get(workspaceContext,
+						types.NamespacedName{
+							Name:      multiWorkspaceRegisteredCluster,
+							Namespace: multiWorkspaceNamespace,
+						},
+						registeredCluster); err != nil {
+						return err
+					}
+					expectedClusterID := strings.ReplaceAll(workspace, ":", "-")
+					if registeredCluster.Status.ClusterID != expectedClusterID {
+						return fmt.Errorf("Expecting clusterID %s, got %s", expectedClusterID, registeredCluster.Status.ClusterID)
+					}
+					return nil
+				}, 60, 3).Should(BeNil())
+			})
+		}
+
+		for _, workspace := range workspaces {
+			workspaceContext := workspaceContexts[workspace]
+			By(fmt.Sprintf("Deleting registeredcluster in workspace %s", workspace), func() {
+				Eventually(func() error {
+					registeredCluster := &singaporev1alpha1.RegisteredCluster{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      multiWorkspaceRegisteredCluster,
+							Namespace: multiWorkspaceNamespace,
+						},
+					}
+					if err := test.ComputeAdminRuntimeClient.Delete(workspaceContext, registeredCluster); err != nil && !errors.IsNotFound(err) {
+						return err
+					}
+					return nil
+				}, 60, 1).Should(BeNil())
+			})
+		}
+	})
+})
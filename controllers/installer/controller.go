@@ -5,6 +5,7 @@ package installer
 import (
 	"errors"
 	"os"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -37,9 +38,12 @@ var (
 )
 
 type installerOptions struct {
-	metricsAddr          string
-	probeAddr            string
-	enableLeaderElection bool
+	metricsAddr              string
+	probeAddr                string
+	enableLeaderElection     bool
+	deletionWorkers          int
+	upgradeTimeout           time.Duration
+	rollbackOnUpgradeFailure bool
 }
 
 func init() {
@@ -64,6 +68,12 @@ func NewInstaller() *cobra.Command {
 	cmd.Flags().BoolVar(&o.enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	cmd.Flags().IntVar(&o.deletionWorkers, "deletion-workers", 1,
+		"The number of parallel deletions of the uninstall, the deletions are sequential if 1.")
+	cmd.Flags().DurationVar(&o.upgradeTimeout, "upgrade-timeout", defaultUpgradeTimeout,
+		"The time the compute-operator deployment is given to roll out a new image.")
+	cmd.Flags().BoolVar(&o.rollbackOnUpgradeFailure, "rollback-on-upgrade-failure", false,
+		"Roll the compute-operator deployment back to its previous image if the new one is not rolled out in time.")
 	return cmd
 }
 
@@ -102,15 +112,33 @@ func (o *installerOptions) run() {
 		os.Exit(1)
 	}
 
+	skipWebhook := os.Getenv("SKIP_WEBHOOK") == "true"
+
+	setupLog.Info("Effective configuration",
+		"controllerNamespace", controllerNamespace,
+		"controllerImage", controllerImage,
+		"webhookEnabled", !skipWebhook,
+		"metricsAddr", o.metricsAddr,
+		"probeAddr", o.probeAddr,
+		"enableLeaderElection", o.enableLeaderElection,
+		"deletionWorkers", o.deletionWorkers,
+		"upgradeTimeout", o.upgradeTimeout,
+		"rollbackOnUpgradeFailure", o.rollbackOnUpgradeFailure)
+
 	if err = (&ClusterRegistrarReconciler{
-		Client:              mgr.GetClient(),
-		KubeClient:          kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie()),
-		DynamicClient:       dynamic.NewForConfigOrDie(ctrl.GetConfigOrDie()),
-		APIExtensionClient:  apiextensionsclient.NewForConfigOrDie(ctrl.GetConfigOrDie()),
-		Log:                 ctrl.Log.WithName("controllers").WithName("Installer"),
-		Scheme:              mgr.GetScheme(),
-		ControllerNamespace: controllerNamespace,
-		ControllerImage:     controllerImage,
+		Client:                   mgr.GetClient(),
+		KubeClient:               kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie()),
+		DynamicClient:            dynamic.NewForConfigOrDie(ctrl.GetConfigOrDie()),
+		APIExtensionClient:       apiextensionsclient.NewForConfigOrDie(ctrl.GetConfigOrDie()),
+		Log:                      ctrl.Log.WithName("controllers").WithName("Installer"),
+		Scheme:                   mgr.GetScheme(),
+		Recorder:                 mgr.GetEventRecorderFor("compute-operator-installer"),
+		ControllerNamespace:      controllerNamespace,
+		ControllerImage:          controllerImage,
+		SkipWebhook:              skipWebhook,
+		DeletionWorkers:          o.deletionWorkers,
+		UpgradeTimeout:           o.upgradeTimeout,
+		RollbackOnUpgradeFailure: o.rollbackOnUpgradeFailure,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Installer")
 		os.Exit(1)
@@ -4,7 +4,8 @@ package installer
 
 import (
 	"context"
-	"os"
+	"fmt"
+	"time"
 
 	// "fmt"
 	// "os"
@@ -13,25 +14,30 @@ import (
 	giterrors "github.com/pkg/errors"
 
 	admissionregistration "k8s.io/api/admissionregistration/v1"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-
-	// "sigs.k8s.io/controller-runtime/pkg/handler"
-
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-
-	// "sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
 	"github.com/stolostron/applier/pkg/apply"
@@ -54,16 +60,104 @@ type ClusterRegistrarReconciler struct {
 	APIExtensionClient  apiextensionsclient.Interface
 	Log                 logr.Logger
 	Scheme              *runtime.Scheme
+	Recorder            record.EventRecorder
 	ControllerNamespace string
 	ControllerImage     string
+	// DeletionWorkers is the number of parallel deletions of the uninstall, the deletions are sequential if 1 or less.
+	DeletionWorkers int
+	// SkipWebhook disables the webhook install, used by the functional tests.
+	SkipWebhook bool
+	// UpgradeTimeout is the time the compute-operator Deployment is given to roll out a new image from the
+	// UpgradeStartedAnnotation, defaultUpgradeTimeout if zero.
+	UpgradeTimeout time.Duration
+	// RollbackOnUpgradeFailure rolls the compute-operator Deployment back to its previous image if the new one
+	// is not rolled out within the UpgradeTimeout.
+	RollbackOnUpgradeFailure bool
+}
+
+// WebhookInstalledAnnotation is set on the ClusterRegistrar once the webhook is installed,
+// so the deletion only cleans up the webhook resources which were created.
+const WebhookInstalledAnnotation = "clusterregistrar.singapore.open-cluster-management.io/webhook-installed"
+
+// FailedUpgradeImageAnnotation is set on the ClusterRegistrar to the image whose upgrade was rolled back, so the
+// next reconciles keep the previous image instead of retrying the upgrade until the ControllerImage changes.
+const FailedUpgradeImageAnnotation = "clusterregistrar.singapore.open-cluster-management.io/failed-upgrade-image"
+
+// UpgradeStartedAnnotation is set on the ClusterRegistrar to the RFC3339 time the ControllerImage was applied to the
+// compute-operator Deployment, the rollout is checked on the next reconciles until it completes or the UpgradeTimeout
+// elapses from that time.
+const UpgradeStartedAnnotation = "clusterregistrar.singapore.open-cluster-management.io/upgrade-started"
+
+// UpgradePreviousImageAnnotation is set on the ClusterRegistrar to the image replaced by the upgrade in progress,
+// the compute-operator Deployment is rolled back to it if RollbackOnUpgradeFailure.
+const UpgradePreviousImageAnnotation = "clusterregistrar.singapore.open-cluster-management.io/upgrade-previous-image"
+
+// installerValues are the values of the templates of the installed resources
+type installerValues struct {
+	Image           string
+	ImagePullPolicy string
+	Resources       corev1.ResourceRequirements
+	Namespace       string
+	FailurePolicy   string
+	// LeaderElection enables the leader election of the compute-operator with the LeaseDuration and RenewDeadline
+	LeaderElection bool
+	LeaseDuration  string
+	RenewDeadline  string
 }
 
+// The default resource requests and limits of the compute-operator container
+const (
+	defaultOperatorCPURequest    = "50m"
+	defaultOperatorMemoryRequest = "50Mi"
+	defaultOperatorCPULimit      = "100m"
+	defaultOperatorMemoryLimit   = "256Mi"
+)
+
+// The templates of the installed resources, the deletion renders the same templates so it deletes the resources
+// with the names they were created with.
+const (
+	operatorDeploymentFile   = "compute-operator/manager.yaml"
+	operatorClusterRoleFile  = "compute-operator/clusterrole.yaml"
+	webhookDeploymentFile    = "webhook/webhook.yaml"
+	webhookAPIServiceFile    = "webhook/webhook_apiservice.yaml"
+	webhookConfigurationFile = "webhook/webhook_validating_config.yaml"
+)
+
+var (
+	// operatorFiles are the templates of the compute-operator resources applied before its Deployment,
+	// its ClusterRole is reconciled apart so a drift of its rules is reported.
+	operatorFiles = []string{
+		"compute-operator/service_account.yaml",
+		"compute-operator/leader_election_role.yaml",
+		"compute-operator/leader_election_role_binding.yaml",
+		"compute-operator/clusterrole_binding.yaml",
+	}
+	// webhookFiles are the templates of the webhook resources applied before its Deployment
+	webhookFiles = []string{
+		"webhook/service_account.yaml",
+		"webhook/webhook_clusterrole.yaml",
+		"webhook/webhook_clusterrolebinding.yaml",
+		"webhook/webhook_service.yaml",
+	}
+)
+
+// installationResyncPeriod is the period the deployments are checked again until the installation is available
+const installationResyncPeriod = 10 * time.Second
+
+const (
+	// defaultUpgradeTimeout is the default time the compute-operator Deployment is given to roll out a new image
+	defaultUpgradeTimeout = 5 * time.Minute
+)
+
+// operatorContainerName is the name of the compute-operator container in its Deployment
+const operatorContainerName = "manager"
+
 // +kubebuilder:rbac:groups="",resources={namespaces, pods},verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources={services,serviceaccounts,configmaps},verbs=get;create;update;list;watch;delete
 
 // +kubebuilder:rbac:groups="apps",resources={deployments},verbs=get;create;update;list;watch;delete
 
-// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources={clusterroles},verbs=escalate;get;create;update;delete;bind;list
+// +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources={clusterroles},verbs=escalate;get;create;update;delete;bind;list;watch
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources={clusterrolebindings},verbs=get;create;update;delete;list;watch
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources={roles},verbs=get;create;update;delete;escalate;bind;list;watch
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources={rolebindings},verbs=get;create;update;delete;list;watch
@@ -74,6 +168,8 @@ type ClusterRegistrarReconciler struct {
 // +kubebuilder:rbac:groups="apiregistration.k8s.io",resources={apiservices},verbs=get;create;update;list;watch;delete
 
 // +kubebuilder:rbac:groups="singapore.open-cluster-management.io",resources={clusterregistrars},verbs=get;create;update;list;watch;delete
+// +kubebuilder:rbac:groups="singapore.open-cluster-management.io",resources={clusterregistrars/status},verbs=get;update;patch
+// +kubebuilder:rbac:groups="";events.k8s.io,resources=events,verbs=create;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -134,6 +230,16 @@ func (r *ClusterRegistrarReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	if !r.isInstallationAvailable(instance) {
+		logger.V(1).Info("installation not yet available")
+		return ctrl.Result{RequeueAfter: installationResyncPeriod}, nil
+	}
+
+	if isUpgradeInProgress(instance) {
+		logger.V(1).Info("upgrade not yet rolled out")
+		return ctrl.Result{RequeueAfter: installationResyncPeriod}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -145,257 +251,234 @@ func (r *ClusterRegistrarReconciler) processClusterRegistrarCreation(ctx context
 	readerDeploy := deploy.GetScenarioResourcesReader()
 
 	//Deploy dex operator
-	files := []string{
-		"compute-operator/service_account.yaml",
-		"compute-operator/leader_election_role.yaml",
-		"compute-operator/leader_election_role_binding.yaml",
-		"compute-operator/clusterrole.yaml",
-		"compute-operator/clusterrole_binding.yaml",
-	}
+	values := r.getInstallerValues(clusterRegistrar)
 
-	values := struct {
-		Image     string
-		Namespace string
-	}{
-		Image:     r.ControllerImage,
-		Namespace: r.ControllerNamespace,
+	_, err := applier.ApplyDirectly(readerDeploy, values, false, "", operatorFiles...)
+	if err != nil {
+		return giterrors.WithStack(err)
 	}
 
-	_, err := applier.ApplyDirectly(readerDeploy, values, false, "", files...)
+	b, err := applier.MustTemplateAsset(readerDeploy, values, "", operatorClusterRoleFile)
 	if err != nil {
 		return giterrors.WithStack(err)
 	}
-
-	files = []string{
-		"compute-operator/manager.yaml",
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(b, clusterRole); err != nil {
+		return giterrors.WithStack(err)
+	}
+	if err := r.createOrUpdateClusterRole(ctx, clusterRegistrar, clusterRole); err != nil {
+		return err
 	}
 
-	_, err = applier.ApplyDeployments(readerDeploy, values, false, "", files...)
+	operatorDeployment, err := renderObjects(values, operatorDeploymentFile)
 	if err != nil {
-		return giterrors.WithStack(err)
+		return err
+	}
+	previousImage, err := r.getDeploymentImage(ctx, operatorDeployment[0].GetName(), operatorContainerName)
+	if err != nil {
+		return err
 	}
 
-	//Deploy webhook
-	if os.Getenv("SKIP_WEBHOOK") != "true" {
-		return r.deployWebhook(ctx, applier, readerDeploy, values)
-	} else {
-		return nil
+	// The image of a rolled back upgrade is not applied again, the Deployment keeps the previous image
+	operatorValues := values
+	upgradeSkipped := len(previousImage) != 0 && previousImage != values.Image &&
+		clusterRegistrar.GetAnnotations()[FailedUpgradeImageAnnotation] == values.Image
+	if upgradeSkipped {
+		r.Log.V(1).Info("skip the upgrade of compute-operator, it was rolled back", "image", values.Image)
+		operatorValues.Image = previousImage
+		operatorValues.ImagePullPolicy = string(getOperatorImagePullPolicy(clusterRegistrar, previousImage))
 	}
-}
 
-func (r *ClusterRegistrarReconciler) processClusterRegistrarDeletion(ctx context.Context, clusterRegistrar *singaporev1alpha1.ClusterRegistrar) error {
-	r.Log.Info("processClusterRegistrarDeletion", "Name", clusterRegistrar.Name)
-	//Delete operator deployment
-	r.Log.Info("Delete deployment", "name", "compute-operator-manager", "namespace", r.ControllerNamespace)
-	clusterRegOperatorDeployment := &appsv1.Deployment{}
-	err := r.Client.Get(ctx,
-		types.NamespacedName{
-			Name:      "compute-operator-manager",
-			Namespace: r.ControllerNamespace,
-		}, clusterRegOperatorDeployment)
-	switch {
-	case errors.IsNotFound(err):
-	case err == nil:
-		if err := r.Client.Delete(ctx, clusterRegOperatorDeployment, &client.DeleteOptions{}); err != nil {
-			return giterrors.WithStack(err)
+	// The upgrade is recorded before the image is applied, so its rollout is checked even if the reconcile
+	// stops in between.
+	if !upgradeSkipped && len(previousImage) != 0 && previousImage != values.Image {
+		if err := r.startUpgrade(ctx, clusterRegistrar, previousImage); err != nil {
+			return err
 		}
-	default:
-		return giterrors.WithStack(err)
 	}
 
-	r.Log.Info("Delete roleBinding", "name", "compute-operator-leader-election-rolebinding", "namespace", r.ControllerNamespace)
-	clusterRegOperatorLeaderElectionRoleBinding := &rbacv1.RoleBinding{}
-	err = r.Client.Get(ctx,
-		types.NamespacedName{Name: "compute-operator-leader-election-rolebinding", Namespace: r.ControllerNamespace},
-		clusterRegOperatorLeaderElectionRoleBinding)
-	switch {
-	case errors.IsNotFound(err):
-	case err == nil:
-		if err := r.Client.Delete(ctx, clusterRegOperatorLeaderElectionRoleBinding, &client.DeleteOptions{}); err != nil {
-			return giterrors.WithStack(err)
-		}
-	default:
+	_, err = applier.ApplyDeployments(readerDeploy, operatorValues, false, "", operatorDeploymentFile)
+	if err != nil {
 		return giterrors.WithStack(err)
 	}
 
-	r.Log.Info("Delete ClusterRoleBinding", "name", "compute-operator-manager-rolebinding", "namespace", r.ControllerNamespace)
-	clusterRegOperatorClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
-	err = r.Client.Get(ctx,
-		types.NamespacedName{Name: "compute-operator-manager-rolebinding", Namespace: r.ControllerNamespace},
-		clusterRegOperatorClusterRoleBinding)
 	switch {
-	case errors.IsNotFound(err):
-	case err == nil:
-		if err := r.Client.Delete(ctx, clusterRegOperatorClusterRoleBinding, &client.DeleteOptions{}); err != nil {
-			return giterrors.WithStack(err)
+	case upgradeSkipped:
+	case isUpgradeInProgress(clusterRegistrar):
+		if err := r.upgradeOperator(ctx, clusterRegistrar, applier, readerDeploy,
+			operatorDeployment[0].GetName()); err != nil {
+			return err
 		}
 	default:
-		return giterrors.WithStack(err)
+		if err := r.syncUpgradeCondition(ctx, clusterRegistrar, operatorDeployment[0].GetName()); err != nil {
+			return err
+		}
 	}
 
-	r.Log.Info("Delete serviceAccount", "name", "compute-operator-manager", "namespace", r.ControllerNamespace)
-	clusterRegOperatorServiceAccount := &corev1.ServiceAccount{}
-	err = r.Client.Get(ctx,
-		types.NamespacedName{Name: "compute-operator-manager", Namespace: r.ControllerNamespace},
-		clusterRegOperatorServiceAccount)
-	switch {
-	case errors.IsNotFound(err):
-	case err == nil:
-		if err := r.Client.Delete(ctx, clusterRegOperatorServiceAccount, &client.DeleteOptions{}); err != nil {
-			return giterrors.WithStack(err)
-		}
-	default:
-		return giterrors.WithStack(err)
+	if err := r.syncDeploymentCondition(ctx, clusterRegistrar,
+		singaporev1alpha1.ClusterRegistrarConditionOperatorDeployed, operatorDeployment[0].GetName()); err != nil {
+		return err
 	}
 
-	r.Log.Info("Delete ClusterRole", "name", "compute-operator-manager-role", "namespace", r.ControllerNamespace)
-	clusterRegOperatorClusterRole := &rbacv1.ClusterRole{}
-	err = r.Client.Get(ctx,
-		types.NamespacedName{Name: "compute-operator-manager-role"},
-		clusterRegOperatorClusterRole)
-	switch {
-	case errors.IsNotFound(err):
-	case err == nil:
-		if err := r.Client.Delete(ctx, clusterRegOperatorClusterRole, &client.DeleteOptions{}); err != nil {
+	//Deploy webhook
+	if r.SkipWebhook {
+		return nil
+	}
+	// The annotation is set before the first webhook resource is applied, so a partial install is cleaned up
+	// by the deletion even if the webhook is skipped by then.
+	if clusterRegistrar.GetAnnotations()[WebhookInstalledAnnotation] != "true" {
+		annotations := clusterRegistrar.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[WebhookInstalledAnnotation] = "true"
+		clusterRegistrar.SetAnnotations(annotations)
+		if err := r.Client.Update(ctx, clusterRegistrar); err != nil {
 			return giterrors.WithStack(err)
 		}
-	default:
-		return giterrors.WithStack(err)
 	}
+	return r.deployWebhook(ctx, clusterRegistrar, applier, readerDeploy, values)
+}
 
-	r.Log.Info("Delete Role", "name", "leader-election-operator-role", "namespace", r.ControllerNamespace)
-	clusterRegOperatorRole := &rbacv1.Role{}
-	err = r.Client.Get(ctx,
-		types.NamespacedName{Name: "leader-election-operator-role", Namespace: r.ControllerNamespace},
-		clusterRegOperatorRole)
-	switch {
-	case errors.IsNotFound(err):
-	case err == nil:
-		if err := r.Client.Delete(ctx, clusterRegOperatorRole, &client.DeleteOptions{}); err != nil {
-			return giterrors.WithStack(err)
-		}
-	default:
-		return giterrors.WithStack(err)
+// isWebhookInstalled returns true if the webhook of the ClusterRegistrar was installed. The SkipWebhook field
+// only decides whether the webhook is installed, the deletion relies on the WebhookInstalledAnnotation so it
+// agrees with the creation even if SkipWebhook changed in between. A ClusterRegistrar installed before the
+// annotation existed has the webhook if it is enabled.
+func (r *ClusterRegistrarReconciler) isWebhookInstalled(clusterRegistrar *singaporev1alpha1.ClusterRegistrar) bool {
+	return clusterRegistrar.GetAnnotations()[WebhookInstalledAnnotation] == "true" || !r.SkipWebhook
+}
+
+func (r *ClusterRegistrarReconciler) processClusterRegistrarDeletion(ctx context.Context, clusterRegistrar *singaporev1alpha1.ClusterRegistrar) error {
+	r.Log.Info("processClusterRegistrarDeletion", "Name", clusterRegistrar.Name)
+	values := r.getInstallerValues(clusterRegistrar)
+
+	//Delete operator
+	objects, err := renderObjects(values, append([]string{operatorDeploymentFile, operatorClusterRoleFile}, operatorFiles...)...)
+	if err != nil {
+		return err
 	}
 
-	// // Do not delete webhook on functional test as it is not installed
-	// pod := &corev1.Pod{}
-	// if err := r.Client.Get(ctx, types.NamespacedName{Name: podName, Namespace: r.ControllerNamespace}, pod); err != nil {
-	// 	return err
-	// }
-	// r.Log.Info("Pod", "Name", pod.Name, "Namespace", pod.Namespace, "deletiontimeStamp", pod.DeletionTimestamp)
-	// if strings.Contains(pod.Spec.Containers[0].Image, "coverage") {
-	// 	return nil
-	// }
-
-	if os.Getenv("SKIP_WEBHOOK") != "true" {
-		//Delete webhook
-		r.Log.Info("Delete Deployment", "name", "compute-webhook-service", "namespace", r.ControllerNamespace)
-		webhookDeployment := &appsv1.Deployment{}
-		err = r.Client.Get(ctx,
-			types.NamespacedName{Name: "compute-webhook-service", Namespace: r.ControllerNamespace},
-			webhookDeployment)
-		switch {
-		case errors.IsNotFound(err):
-		case err == nil:
-			if err := r.Client.Delete(ctx, webhookDeployment, &client.DeleteOptions{}); err != nil {
-				return giterrors.WithStack(err)
-			}
-		default:
-			return giterrors.WithStack(err)
-		}
+	// Only delete the webhook if it was installed, the functional tests run without the webhook.
+	if !r.isWebhookInstalled(clusterRegistrar) {
+		r.Log.Info("webhook not installed, skipping its deletion")
+		return r.deleteObjects(ctx, objects...)
+	}
 
-		r.Log.Info("Delete APIService", "name", "v1alpha1.admission.singapore.open-cluster-management.io")
-		apiService := &apiregistrationv1.APIService{}
-		err = r.Client.Get(ctx,
-			types.NamespacedName{Name: "v1alpha1.admission.singapore.open-cluster-management.io"},
-			apiService)
-		switch {
-		case errors.IsNotFound(err):
-		case err == nil:
-			if err := r.Client.Delete(ctx, apiService, &client.DeleteOptions{}); err != nil {
-				return giterrors.WithStack(err)
-			}
-		default:
-			return giterrors.WithStack(err)
+	// The webhook registration is deleted before the webhook serving it. With the Fail policy, a
+	// ValidatingWebhookConfiguration pointing to a deleted webhook rejects all the RegisteredCluster
+	// and ClusterRegistrar requests, and the webhook is reached through the APIService.
+	for _, file := range []string{webhookConfigurationFile, webhookAPIServiceFile} {
+		registration, err := renderObjects(values, file)
+		if err != nil {
+			return err
 		}
-
-		r.Log.Info("Delete ClusterRoleBinding", "name", "compute-webhook-service")
-		webHookClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
-		err = r.Client.Get(ctx,
-			types.NamespacedName{Name: "compute-webhook-service"},
-			webHookClusterRoleBinding)
-		switch {
-		case errors.IsNotFound(err):
-		case err == nil:
-			if err := r.Client.Delete(ctx, webHookClusterRoleBinding, &client.DeleteOptions{}); err != nil {
-				return giterrors.WithStack(err)
-			}
-		default:
-			return giterrors.WithStack(err)
+		if err := r.deleteObjects(ctx, registration...); err != nil {
+			return err
 		}
+	}
 
-		r.Log.Info("Delete ClusterRole", "name", "compute-webhook-service")
-		webHookClusterRole := &rbacv1.ClusterRole{}
-		err = r.Client.Get(ctx,
-			types.NamespacedName{Name: "compute-webhook-service"},
-			webHookClusterRole)
-		switch {
-		case errors.IsNotFound(err):
-		case err == nil:
-			if err := r.Client.Delete(ctx, webHookClusterRole, &client.DeleteOptions{}); err != nil {
-				return giterrors.WithStack(err)
-			}
-		default:
-			return giterrors.WithStack(err)
-		}
+	//Delete webhook
+	webhookObjects, err := renderObjects(values, append([]string{webhookDeploymentFile}, webhookFiles...)...)
+	if err != nil {
+		return err
+	}
+	return r.deleteObjects(ctx, append(objects, webhookObjects...)...)
+}
 
-		r.Log.Info("Delete serviceAccount", "name", "compute-webhook-service", "namespace", r.ControllerNamespace)
-		webHookServiceAccount := &corev1.ServiceAccount{}
-		err = r.Client.Get(ctx,
-			types.NamespacedName{Name: "compute-webhook-service", Namespace: r.ControllerNamespace},
-			webHookServiceAccount)
-		switch {
-		case errors.IsNotFound(err):
-		case err == nil:
-			if err := r.Client.Delete(ctx, webHookServiceAccount, &client.DeleteOptions{}); err != nil {
-				return giterrors.WithStack(err)
-			}
-		default:
-			return giterrors.WithStack(err)
-		}
+// getInstallerValues returns the values of the templates of the resources installed for the ClusterRegistrar.
+func (r *ClusterRegistrarReconciler) getInstallerValues(clusterRegistrar *singaporev1alpha1.ClusterRegistrar) installerValues {
+	leaderElection := clusterRegistrar.Spec.OperatorLeaderElection
+	leaseDuration, renewDeadline := helpers.GetLeaderElectionDurations(leaderElection)
+	return installerValues{
+		Image:           r.ControllerImage,
+		ImagePullPolicy: string(getOperatorImagePullPolicy(clusterRegistrar, r.ControllerImage)),
+		Resources:       getOperatorResources(clusterRegistrar),
+		Namespace:       r.ControllerNamespace,
+		FailurePolicy:   string(getWebhookFailurePolicy(clusterRegistrar)),
+		LeaderElection:  leaderElection == nil || !leaderElection.Disabled,
+		LeaseDuration:   leaseDuration.String(),
+		RenewDeadline:   renewDeadline.String(),
+	}
+}
 
-		r.Log.Info("Delete Service", "name", "compute-webhook-service", "namespace", r.ControllerNamespace)
-		service := &corev1.Service{}
-		err = r.Client.Get(ctx,
-			types.NamespacedName{Name: "compute-webhook-service", Namespace: r.ControllerNamespace},
-			service)
-		switch {
-		case errors.IsNotFound(err):
-		case err == nil:
-			if err := r.Client.Delete(ctx, service, &client.DeleteOptions{}); err != nil {
-				return giterrors.WithStack(err)
-			}
-		default:
-			return giterrors.WithStack(err)
+// getOperatorImagePullPolicy returns the pull policy of the compute-operator image of the ClusterRegistrar,
+// the kubernetes default of the image if not set.
+func getOperatorImagePullPolicy(clusterRegistrar *singaporev1alpha1.ClusterRegistrar, image string) corev1.PullPolicy {
+	if len(clusterRegistrar.Spec.OperatorImagePullPolicy) != 0 {
+		return clusterRegistrar.Spec.OperatorImagePullPolicy
+	}
+	return helpers.GetDefaultImagePullPolicy(image)
+}
+
+// getOperatorResources returns the resource requests and limits of the compute-operator container, the defaults
+// overridden by the ones of the ClusterRegistrar spec.
+func getOperatorResources(clusterRegistrar *singaporev1alpha1.ClusterRegistrar) corev1.ResourceRequirements {
+	operatorResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(defaultOperatorCPURequest),
+			corev1.ResourceMemory: resource.MustParse(defaultOperatorMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(defaultOperatorCPULimit),
+			corev1.ResourceMemory: resource.MustParse(defaultOperatorMemoryLimit),
+		},
+	}
+	if clusterRegistrar.Spec.OperatorResources == nil {
+		return operatorResources
+	}
+	for name, quantity := range clusterRegistrar.Spec.OperatorResources.Requests {
+		operatorResources.Requests[name] = quantity
+	}
+	for name, quantity := range clusterRegistrar.Spec.OperatorResources.Limits {
+		operatorResources.Limits[name] = quantity
+	}
+	return operatorResources
+}
+
+// renderObjects returns the objects of the templates rendered with the values.
+func renderObjects(values installerValues, files ...string) ([]client.Object, error) {
+	applier := apply.NewApplierBuilder().Build()
+	readerDeploy := deploy.GetScenarioResourcesReader()
+	objects := make([]client.Object, 0, len(files))
+	for _, file := range files {
+		b, err := applier.MustTemplateAsset(readerDeploy, values, "", file)
+		if err != nil {
+			return nil, giterrors.WithStack(err)
 		}
+		object := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(b, &object.Object); err != nil {
+			return nil, giterrors.WithStack(err)
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
 
-		r.Log.Info("Delete ValidatingWebhookConfiguration", "name", "compute-webhook-service", "namespace", r.ControllerNamespace)
-		validationWebhook := &admissionregistration.ValidatingWebhookConfiguration{}
-		err = r.Client.Get(ctx,
-			types.NamespacedName{Name: "compute-webhook-service", Namespace: r.ControllerNamespace},
-			validationWebhook)
-		switch {
-		case errors.IsNotFound(err):
-		case err == nil:
-			if err := r.Client.Delete(ctx, validationWebhook, &client.DeleteOptions{}); err != nil {
-				return giterrors.WithStack(err)
+// deleteObjects deletes the objects, which must not depend on each other. The objects are deleted
+// in order if DeletionWorkers is 1 or less, else in parallel by DeletionWorkers workers.
+func (r *ClusterRegistrarReconciler) deleteObjects(ctx context.Context, objects ...client.Object) error {
+	if r.DeletionWorkers <= 1 {
+		for _, object := range objects {
+			if err := r.deleteObject(ctx, object); err != nil {
+				return err
 			}
-		default:
-			return giterrors.WithStack(err)
 		}
+		return nil
 	}
 
+	errs := make([]error, len(objects))
+	workqueue.ParallelizeUntil(ctx, r.DeletionWorkers, len(objects), func(i int) {
+		errs[i] = r.deleteObject(ctx, objects[i])
+	})
+	return utilerrors.NewAggregate(errs)
+}
+
+// deleteObject deletes the object, a missing object is not an error.
+func (r *ClusterRegistrarReconciler) deleteObject(ctx context.Context, object client.Object) error {
+	r.Log.Info("Delete", "kind", object.GetObjectKind().GroupVersionKind().Kind, "name", object.GetName(), "namespace", object.GetNamespace())
+	if err := r.Client.Delete(ctx, object, &client.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return giterrors.WithStack(err)
+	}
 	return nil
 }
 
@@ -429,40 +512,75 @@ func (r *ClusterRegistrarReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return giterrors.WithStack(err)
 	}
 
+	// The compute-operator ClusterRole is watched so a drift of its rules is restored
+	clusterRoles, err := renderObjects(installerValues{Namespace: r.ControllerNamespace}, operatorClusterRoleFile)
+	if err != nil {
+		return err
+	}
+	clusterRoleName := clusterRoles[0].GetName()
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&singaporev1alpha1.ClusterRegistrar{}).
+		Watches(&source.Kind{Type: &rbacv1.ClusterRole{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterRegistrarsForClusterRole),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+				return o.GetName() == clusterRoleName
+			}))).
 		Complete(r)
 }
 
 func (r *ClusterRegistrarReconciler) deployWebhook(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
 	applier apply.Applier,
 	readerDeploy *asset.ScenarioResourcesReader,
-	values struct {
-		Image     string
-		Namespace string
-	}) error {
-	files := []string{
-		"webhook/service_account.yaml",
-		"webhook/webhook_clusterrole.yaml",
-		"webhook/webhook_clusterrolebinding.yaml",
-		"webhook/webhook_service.yaml",
+	values installerValues) error {
+	_, err := applier.ApplyDirectly(readerDeploy, values, false, "", webhookFiles...)
+	if err != nil {
+		return giterrors.WithStack(err)
 	}
 
-	_, err := applier.ApplyDirectly(readerDeploy, values, false, "", files...)
+	_, err = applier.ApplyDeployments(readerDeploy, values, false, "", webhookDeploymentFile)
 	if err != nil {
 		return giterrors.WithStack(err)
 	}
 
-	files = []string{
-		"webhook/webhook.yaml",
+	// The admission requests must not be routed to the webhook before it serves, the registration is skipped
+	// until the webhook Deployment has a ready replica and the installation is requeued meanwhile.
+	webhookDeployment, err := renderObjects(values, webhookDeploymentFile)
+	if err != nil {
+		return err
+	}
+	if err := r.syncDeploymentCondition(ctx, clusterRegistrar,
+		singaporev1alpha1.ClusterRegistrarConditionWebhookDeployed, webhookDeployment[0].GetName()); err != nil {
+		return err
+	}
+	ready, err := r.isDeploymentReady(ctx, webhookDeployment[0].GetName())
+	if err != nil {
+		return err
+	}
+	if !ready {
+		r.Log.V(1).Info("webhook not yet ready", "deployment", webhookDeployment[0].GetName())
+		return nil
 	}
 
-	_, err = applier.ApplyDeployments(readerDeploy, values, false, "", files...)
+	b, err := applier.MustTemplateAsset(readerDeploy, values, "", webhookAPIServiceFile)
 	if err != nil {
 		return giterrors.WithStack(err)
 	}
 
-	b, err := applier.MustTemplateAsset(readerDeploy, values, "", "webhook/webhook_validating_config.yaml")
+	apiService := &apiregistrationv1.APIService{}
+	err = yaml.Unmarshal(b, apiService)
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	if err := r.createOrUpdateAPIService(ctx, apiService); err != nil {
+		return err
+	}
+	if err := r.syncAPIServiceCondition(ctx, clusterRegistrar, apiService.Name); err != nil {
+		return err
+	}
+
+	b, err = applier.MustTemplateAsset(readerDeploy, values, "", webhookConfigurationFile)
 	if err != nil {
 		return giterrors.WithStack(err)
 	}
@@ -473,26 +591,431 @@ func (r *ClusterRegistrarReconciler) deployWebhook(ctx context.Context,
 		return giterrors.WithStack(err)
 	}
 
-	if err := r.Client.Create(ctx, validationWebhookConfiguration, &client.CreateOptions{}); err != nil {
-		if !errors.IsAlreadyExists(err) {
-			return giterrors.WithStack(err)
+	return r.createOrUpdateValidatingWebhookConfiguration(ctx, validationWebhookConfiguration)
+}
+
+// isDeploymentReady returns true if the Deployment of the controller namespace has a ready replica, false if it
+// doesn't exist.
+func (r *ClusterRegistrarReconciler) isDeploymentReady(ctx context.Context, name string) (bool, error) {
+	deployment, err := r.KubeClient.AppsV1().Deployments(r.ControllerNamespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, giterrors.WithStack(err)
+	}
+	return deployment.Status.ReadyReplicas > 0, nil
+}
+
+// getDeploymentImage returns the image of the container of the Deployment of the controller namespace,
+// empty if the Deployment or the container doesn't exist.
+func (r *ClusterRegistrarReconciler) getDeploymentImage(ctx context.Context, name, containerName string) (string, error) {
+	deployment, err := r.KubeClient.AppsV1().Deployments(r.ControllerNamespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return "", nil
+	case err != nil:
+		return "", giterrors.WithStack(err)
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == containerName {
+			return container.Image, nil
 		}
 	}
+	return "", nil
+}
+
+// startUpgrade records in the annotations of the ClusterRegistrar the upgrade of the compute-operator Deployment
+// from the previous image to the ControllerImage. The previous image of an upgrade still in progress is kept,
+// the Deployment is only rolled back to an image which was rolled out.
+func (r *ClusterRegistrarReconciler) startUpgrade(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	previousImage string) error {
+	r.Log.Info("upgrade compute-operator", "previousImage", previousImage, "image", r.ControllerImage)
+	if recordedImage := clusterRegistrar.GetAnnotations()[UpgradePreviousImageAnnotation]; len(recordedImage) != 0 {
+		previousImage = recordedImage
+	}
+	return r.updateAnnotations(ctx, clusterRegistrar, map[string]string{
+		UpgradeStartedAnnotation:       time.Now().UTC().Format(time.RFC3339),
+		UpgradePreviousImageAnnotation: previousImage,
+	})
+}
 
-	b, err = applier.MustTemplateAsset(readerDeploy, values, "", "webhook/webhook_apiservice.yaml")
+// isUpgradeInProgress returns true if an upgrade of the compute-operator Deployment is recorded in the annotations
+// of the ClusterRegistrar.
+func isUpgradeInProgress(clusterRegistrar *singaporev1alpha1.ClusterRegistrar) bool {
+	return len(clusterRegistrar.GetAnnotations()[UpgradeStartedAnnotation]) != 0
+}
+
+// upgradeOperator checks whether the compute-operator Deployment rolled out the ControllerImage of the upgrade in
+// progress, it doesn't wait for the rollout and the reconcile is requeued until it completes. If it doesn't within
+// the UpgradeTimeout from the UpgradeStartedAnnotation, the UpgradeFailed condition is set, the Deployment is rolled
+// back to the previous image if RollbackOnUpgradeFailure and an error is returned. A rolled back image is recorded
+// in the FailedUpgradeImageAnnotation, the upgrade is only retried once the ControllerImage changes.
+func (r *ClusterRegistrarReconciler) upgradeOperator(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	applier apply.Applier,
+	readerDeploy *asset.ScenarioResourcesReader,
+	name string) error {
+	deployment, err := r.KubeClient.AppsV1().Deployments(r.ControllerNamespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		deployment = nil
+	case err != nil:
+		return giterrors.WithStack(err)
+	}
+	if deployment != nil && helpers.IsDeploymentRolledOut(deployment) {
+		if err := r.updateAnnotations(ctx, clusterRegistrar, map[string]string{
+			UpgradeStartedAnnotation:       "",
+			UpgradePreviousImageAnnotation: "",
+			FailedUpgradeImageAnnotation:   "",
+		}); err != nil {
+			return err
+		}
+		return r.patchConditions(ctx, clusterRegistrar, metav1.Condition{
+			Type:    singaporev1alpha1.ClusterRegistrarConditionUpgradeFailed,
+			Status:  metav1.ConditionFalse,
+			Reason:  "UpgradeSucceeded",
+			Message: fmt.Sprintf("The image %s is rolled out", r.ControllerImage),
+		})
+	}
+
+	timeout := r.UpgradeTimeout
+	if timeout == 0 {
+		timeout = defaultUpgradeTimeout
+	}
+	annotations := clusterRegistrar.GetAnnotations()
+	started, err := time.Parse(time.RFC3339, annotations[UpgradeStartedAnnotation])
 	if err != nil {
+		// An invalid start time restarts the upgrade timeout
+		r.Log.Error(err, "invalid upgrade start time", "annotation", UpgradeStartedAnnotation)
+		return r.updateAnnotations(ctx, clusterRegistrar, map[string]string{
+			UpgradeStartedAnnotation: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+	if time.Since(started) < timeout {
+		r.Log.V(1).Info("wait for the rollout of compute-operator", "image", r.ControllerImage, "started", started)
+		return nil
+	}
+
+	rolloutErr := fmt.Errorf("the deployment %s/%s is not rolled out after %s: %s", r.ControllerNamespace, name, timeout,
+		helpers.GetDeploymentCondition(singaporev1alpha1.ClusterRegistrarConditionOperatorDeployed, deployment).Message)
+	r.Log.Error(rolloutErr, "upgrade of compute-operator failed", "image", r.ControllerImage)
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.ClusterRegistrarConditionUpgradeFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  "RolloutTimeout",
+		Message: fmt.Sprintf("The image %s is not rolled out: %s", r.ControllerImage, rolloutErr),
+	}
+	// The upgrade is over, the rolled back image is recorded so the next reconciles don't apply it again
+	previousImage := annotations[UpgradePreviousImageAnnotation]
+	upgradeAnnotations := map[string]string{
+		UpgradeStartedAnnotation:       "",
+		UpgradePreviousImageAnnotation: "",
+	}
+	if r.RollbackOnUpgradeFailure && len(previousImage) != 0 {
+		values := r.getInstallerValues(clusterRegistrar)
+		values.Image = previousImage
+		values.ImagePullPolicy = string(getOperatorImagePullPolicy(clusterRegistrar, previousImage))
+		if _, err := applier.ApplyDeployments(readerDeploy, values, false, "", operatorDeploymentFile); err != nil {
+			r.Log.Error(err, "rollback of compute-operator failed", "image", previousImage)
+			condition.Message = fmt.Sprintf("%s, the rollback to the image %s failed: %s", condition.Message, previousImage, err)
+		} else {
+			condition.Reason = "RolledBack"
+			condition.Message = fmt.Sprintf("%s, rolled back to the image %s, the upgrade is retried once the image changes",
+				condition.Message, previousImage)
+			upgradeAnnotations[FailedUpgradeImageAnnotation] = r.ControllerImage
+		}
+	}
+	if err := r.updateAnnotations(ctx, clusterRegistrar, upgradeAnnotations); err != nil {
+		return err
+	}
+	if err := r.patchConditions(ctx, clusterRegistrar, condition); err != nil {
+		return err
+	}
+	return rolloutErr
+}
+
+// updateAnnotations sets the annotations of the ClusterRegistrar, an empty value removes the annotation. The
+// ClusterRegistrar is only updated if its annotations change.
+func (r *ClusterRegistrarReconciler) updateAnnotations(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	changes map[string]string) error {
+	annotations := clusterRegistrar.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	changed := false
+	for key, value := range changes {
+		current, ok := annotations[key]
+		switch {
+		case len(value) == 0 && ok:
+			delete(annotations, key)
+		case len(value) != 0 && current != value:
+			annotations[key] = value
+		default:
+			continue
+		}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	clusterRegistrar.SetAnnotations(annotations)
+	if err := r.Client.Update(ctx, clusterRegistrar); err != nil {
 		return giterrors.WithStack(err)
 	}
+	return nil
+}
 
+// syncUpgradeCondition clears the UpgradeFailed condition of the ClusterRegistrar once the compute-operator
+// Deployment which was not rolled back completes its rollout.
+func (r *ClusterRegistrarReconciler) syncUpgradeCondition(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	name string) error {
+	if status, ok := helpers.GetConditionStatus(clusterRegistrar.Status.Conditions,
+		singaporev1alpha1.ClusterRegistrarConditionUpgradeFailed); !ok || status != metav1.ConditionTrue {
+		return nil
+	}
+	deployment, err := r.KubeClient.AppsV1().Deployments(r.ControllerNamespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return giterrors.WithStack(err)
+	}
+	if !helpers.IsDeploymentRolledOut(deployment) {
+		return nil
+	}
+	return r.patchConditions(ctx, clusterRegistrar, metav1.Condition{
+		Type:    singaporev1alpha1.ClusterRegistrarConditionUpgradeFailed,
+		Status:  metav1.ConditionFalse,
+		Reason:  "UpgradeSucceeded",
+		Message: fmt.Sprintf("The image %s is rolled out", r.ControllerImage),
+	})
+}
+
+// syncDeploymentCondition sets the condition of the ClusterRegistrar from the availability of the Deployment
+// of the controller namespace.
+func (r *ClusterRegistrarReconciler) syncDeploymentCondition(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	conditionType string,
+	name string) error {
+	deployment, err := r.KubeClient.AppsV1().Deployments(r.ControllerNamespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		deployment = nil
+	case err != nil:
+		return giterrors.WithStack(err)
+	}
+	return r.patchConditions(ctx, clusterRegistrar, helpers.GetDeploymentCondition(conditionType, deployment))
+}
+
+// syncAPIServiceCondition sets the APIServiceAvailable condition of the ClusterRegistrar from the availability
+// of the APIService.
+func (r *ClusterRegistrarReconciler) syncAPIServiceCondition(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	name string) error {
 	apiService := &apiregistrationv1.APIService{}
-	err = yaml.Unmarshal(b, apiService)
+	err := r.Client.Get(ctx, types.NamespacedName{Name: name}, apiService)
+	switch {
+	case errors.IsNotFound(err):
+		apiService = nil
+	case err != nil:
+		return giterrors.WithStack(err)
+	}
+	return r.patchConditions(ctx, clusterRegistrar,
+		helpers.GetAPIServiceCondition(singaporev1alpha1.ClusterRegistrarConditionAPIServiceAvailable, apiService))
+}
+
+// patchConditions merges the conditions in the ClusterRegistrar status, the status is only patched if it changes.
+func (r *ClusterRegistrarReconciler) patchConditions(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	conditions ...metav1.Condition) error {
+	original := clusterRegistrar.DeepCopy()
+	clusterRegistrar.Status.Conditions = helpers.MergeStatusConditions(clusterRegistrar.Status.Conditions, conditions...)
+	if equality.Semantic.DeepEqual(original.Status, clusterRegistrar.Status) {
+		return nil
+	}
+	if err := r.Client.Status().Patch(ctx, clusterRegistrar, client.MergeFrom(original)); err != nil {
+		return giterrors.WithStack(err)
+	}
+	return nil
+}
+
+// isInstallationAvailable returns true if the installed deployments and APIService are available, the webhook
+// ones are ignored when the webhook is skipped.
+func (r *ClusterRegistrarReconciler) isInstallationAvailable(clusterRegistrar *singaporev1alpha1.ClusterRegistrar) bool {
+	conditionTypes := []string{singaporev1alpha1.ClusterRegistrarConditionOperatorDeployed}
+	if !r.SkipWebhook {
+		conditionTypes = append(conditionTypes,
+			singaporev1alpha1.ClusterRegistrarConditionWebhookDeployed,
+			singaporev1alpha1.ClusterRegistrarConditionAPIServiceAvailable)
+	}
+	for _, conditionType := range conditionTypes {
+		if status, ok := helpers.GetConditionStatus(clusterRegistrar.Status.Conditions, conditionType); !ok || status != metav1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// getWebhookFailurePolicy returns the failurePolicy of the validating webhook of the ClusterRegistrar, Fail if not set.
+func getWebhookFailurePolicy(clusterRegistrar *singaporev1alpha1.ClusterRegistrar) admissionregistration.FailurePolicyType {
+	if len(clusterRegistrar.Spec.WebhookFailurePolicy) == 0 {
+		return admissionregistration.Fail
+	}
+	return clusterRegistrar.Spec.WebhookFailurePolicy
+}
+
+// createOrUpdateValidatingWebhookConfiguration reconciles the webhooks of the ValidatingWebhookConfiguration
+// with the desired ones, the CA bundles injected in the existing webhooks are kept.
+func (r *ClusterRegistrarReconciler) createOrUpdateValidatingWebhookConfiguration(ctx context.Context,
+	desired *admissionregistration.ValidatingWebhookConfiguration) error {
+	validationWebhookConfiguration := &admissionregistration.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: desired.Name},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, validationWebhookConfiguration, func() error {
+		caBundles := map[string][]byte{}
+		for _, webhook := range validationWebhookConfiguration.Webhooks {
+			caBundles[webhook.Name] = webhook.ClientConfig.CABundle
+		}
+		mergeMap(&validationWebhookConfiguration.Labels, desired.Labels)
+		mergeMap(&validationWebhookConfiguration.Annotations, desired.Annotations)
+		existingWebhooks := map[string]admissionregistration.ValidatingWebhook{}
+		for _, webhook := range validationWebhookConfiguration.Webhooks {
+			existingWebhooks[webhook.Name] = webhook
+		}
+		validationWebhookConfiguration.Webhooks = make([]admissionregistration.ValidatingWebhook, len(desired.Webhooks))
+		for i := range desired.Webhooks {
+			webhook := &validationWebhookConfiguration.Webhooks[i]
+			desired.Webhooks[i].DeepCopyInto(webhook)
+			if len(webhook.ClientConfig.CABundle) == 0 {
+				webhook.ClientConfig.CABundle = caBundles[webhook.Name]
+			}
+			if existing, ok := existingWebhooks[webhook.Name]; ok {
+				keepWebhookDefaults(&existing, webhook)
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return giterrors.WithStack(err)
 	}
-	if err := r.Client.Create(ctx, apiService, &client.CreateOptions{}); err != nil {
-		if !errors.IsAlreadyExists(err) {
-			return giterrors.WithStack(err)
+	r.Log.V(1).Info("ValidatingWebhookConfiguration reconciled", "name", desired.Name, "result", result)
+	return nil
+}
+
+// keepWebhookDefaults sets the fields of the desired webhook left unset by the template to the values the apiserver
+// defaulted in the existing webhook, so the configuration is only updated when the templated fields drift.
+func keepWebhookDefaults(existing, desired *admissionregistration.ValidatingWebhook) {
+	if desired.MatchPolicy == nil {
+		desired.MatchPolicy = existing.MatchPolicy
+	}
+	if desired.NamespaceSelector == nil {
+		desired.NamespaceSelector = existing.NamespaceSelector
+	}
+	if desired.ObjectSelector == nil {
+		desired.ObjectSelector = existing.ObjectSelector
+	}
+	if desired.TimeoutSeconds == nil {
+		desired.TimeoutSeconds = existing.TimeoutSeconds
+	}
+	if desired.ClientConfig.Service != nil && desired.ClientConfig.Service.Port == nil && existing.ClientConfig.Service != nil {
+		desired.ClientConfig.Service.Port = existing.ClientConfig.Service.Port
+	}
+	if len(desired.Rules) == len(existing.Rules) {
+		for i := range desired.Rules {
+			if desired.Rules[i].Scope == nil {
+				desired.Rules[i].Scope = existing.Rules[i].Scope
+			}
+		}
+	}
+}
+
+// createOrUpdateAPIService reconciles the spec of the APIService with the desired one,
+// the injected CA bundle is kept.
+func (r *ClusterRegistrarReconciler) createOrUpdateAPIService(ctx context.Context, desired *apiregistrationv1.APIService) error {
+	apiService := &apiregistrationv1.APIService{
+		ObjectMeta: metav1.ObjectMeta{Name: desired.Name},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, apiService, func() error {
+		caBundle := apiService.Spec.CABundle
+		mergeMap(&apiService.Labels, desired.Labels)
+		mergeMap(&apiService.Annotations, desired.Annotations)
+		existingService := apiService.Spec.Service
+		desired.Spec.DeepCopyInto(&apiService.Spec)
+		if len(apiService.Spec.CABundle) == 0 {
+			apiService.Spec.CABundle = caBundle
 		}
+		// The port is defaulted by the apiserver
+		if apiService.Spec.Service != nil && apiService.Spec.Service.Port == nil && existingService != nil {
+			apiService.Spec.Service.Port = existingService.Port
+		}
+		return nil
+	})
+	if err != nil {
+		return giterrors.WithStack(err)
 	}
+	r.Log.V(1).Info("APIService reconciled", "name", desired.Name, "result", result)
 	return nil
 }
+
+// createOrUpdateClusterRole reconciles the rules of the ClusterRole with the desired ones, an event is recorded on
+// the ClusterRegistrar when rules edited out of the installer are restored. The installer is granted the escalate
+// and bind verbs on the ClusterRoles, so it can restore rules it doesn't hold itself.
+func (r *ClusterRegistrarReconciler) createOrUpdateClusterRole(ctx context.Context,
+	clusterRegistrar *singaporev1alpha1.ClusterRegistrar,
+	desired *rbacv1.ClusterRole) error {
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: desired.Name},
+	}
+	drifted := false
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, clusterRole, func() error {
+		mergeMap(&clusterRole.Labels, desired.Labels)
+		mergeMap(&clusterRole.Annotations, desired.Annotations)
+		drifted = len(clusterRole.ResourceVersion) != 0 &&
+			(!equality.Semantic.DeepEqual(clusterRole.Rules, desired.Rules) || clusterRole.AggregationRule != nil)
+		clusterRole.Rules = desired.Rules
+		clusterRole.AggregationRule = nil
+		return nil
+	})
+	if err != nil {
+		return giterrors.WithStack(err)
+	}
+	r.Log.V(1).Info("ClusterRole reconciled", "name", desired.Name, "result", result)
+	if drifted {
+		r.Log.Info("ClusterRole rules restored", "name", desired.Name)
+		r.Recorder.Eventf(clusterRegistrar, corev1.EventTypeWarning, "ClusterRoleDriftCorrected",
+			"The rules of the ClusterRole %s were modified and are restored", desired.Name)
+	}
+	return nil
+}
+
+// clusterRegistrarsForClusterRole returns the requests of all the ClusterRegistrars, which install the
+// compute-operator ClusterRole.
+func (r *ClusterRegistrarReconciler) clusterRegistrarsForClusterRole(o client.Object) []reconcile.Request {
+	clusterRegistrars := &singaporev1alpha1.ClusterRegistrarList{}
+	if err := r.Client.List(context.TODO(), clusterRegistrars); err != nil {
+		r.Log.Error(err, "failed to list the ClusterRegistrars", "clusterRole", o.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(clusterRegistrars.Items))
+	for _, clusterRegistrar := range clusterRegistrars.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterRegistrar.Name}})
+	}
+	return requests
+}
+
+// mergeMap adds the desired entries to the existing map
+func mergeMap(existing *map[string]string, desired map[string]string) {
+	if len(desired) == 0 {
+		return
+	}
+	if *existing == nil {
+		*existing = map[string]string{}
+	}
+	for k, v := range desired {
+		(*existing)[k] = v
+	}
+}
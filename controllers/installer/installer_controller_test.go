@@ -0,0 +1,260 @@
+// Copyright Red Hat
+
+package installer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stolostron/applier/pkg/apply"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/deploy"
+)
+
+func TestProcessClusterRegistrarDeletion(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(testScheme)
+	_ = apiregistrationv1.AddToScheme(testScheme)
+	_ = singaporev1alpha1.AddToScheme(testScheme)
+
+	clusterRegistrar := &singaporev1alpha1.ClusterRegistrar{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster-registrar",
+			Annotations: map[string]string{WebhookInstalledAnnotation: "true"},
+		},
+	}
+	r := &ClusterRegistrarReconciler{
+		Client:              clientfake.NewClientBuilder().WithScheme(testScheme).Build(),
+		Log:                 logr.Discard(),
+		ControllerNamespace: "compute-config",
+		ControllerImage:     "compute-operator:latest",
+		SkipWebhook:         true,
+	}
+
+	// Create the resources of the installation templates
+	files := append([]string{operatorDeploymentFile, operatorClusterRoleFile,
+		webhookDeploymentFile, webhookAPIServiceFile, webhookConfigurationFile},
+		append(operatorFiles, webhookFiles...)...)
+	objects, err := renderObjects(r.getInstallerValues(clusterRegistrar), files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, object := range objects {
+		if err := r.Client.Create(context.TODO(), object); err != nil {
+			t.Fatalf("failed to create %s %s: %v", object.GetObjectKind().GroupVersionKind().Kind, object.GetName(), err)
+		}
+	}
+
+	if err := r.processClusterRegistrarDeletion(context.TODO(), clusterRegistrar); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, object := range objects {
+		leftover := &unstructured.Unstructured{}
+		leftover.SetGroupVersionKind(object.GetObjectKind().GroupVersionKind())
+		err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(object), leftover)
+		if !errors.IsNotFound(err) {
+			t.Errorf("%s %s/%s not deleted: %v", object.GetObjectKind().GroupVersionKind().Kind,
+				object.GetNamespace(), object.GetName(), err)
+		}
+	}
+}
+
+func TestUpgradeOperatorRollback(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(testScheme)
+	_ = singaporev1alpha1.AddToScheme(testScheme)
+
+	clusterRegistrar := &singaporev1alpha1.ClusterRegistrar{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster-registrar",
+			Annotations: map[string]string{
+				UpgradeStartedAnnotation:       time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+				UpgradePreviousImageAnnotation: "compute-operator:v1",
+			},
+		},
+	}
+	r := &ClusterRegistrarReconciler{
+		Client:                   clientfake.NewClientBuilder().WithScheme(testScheme).WithObjects(clusterRegistrar).Build(),
+		KubeClient:               kubefake.NewSimpleClientset(),
+		Log:                      logr.Discard(),
+		ControllerNamespace:      "compute-config",
+		ControllerImage:          "compute-operator:v2",
+		UpgradeTimeout:           time.Millisecond,
+		RollbackOnUpgradeFailure: true,
+	}
+	applier := apply.NewApplierBuilder().
+		WithClient(r.KubeClient, apiextensionsfake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(testScheme)).
+		Build()
+	readerDeploy := deploy.GetScenarioResourcesReader()
+
+	// The fake clientset never rolls out the new image
+	values := r.getInstallerValues(clusterRegistrar)
+	if _, err := applier.ApplyDeployments(readerDeploy, values, false, "", operatorDeploymentFile); err != nil {
+		t.Fatal(err)
+	}
+	objects, err := renderObjects(values, operatorDeploymentFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := objects[0].GetName()
+
+	if err := r.upgradeOperator(context.TODO(), clusterRegistrar, applier, readerDeploy, name); err == nil {
+		t.Fatal("expected the upgrade to fail")
+	}
+
+	image, err := r.getDeploymentImage(context.TODO(), name, operatorContainerName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image != "compute-operator:v1" {
+		t.Errorf("expected the deployment rolled back to compute-operator:v1, got %s", image)
+	}
+	condition := meta.FindStatusCondition(clusterRegistrar.Status.Conditions, singaporev1alpha1.ClusterRegistrarConditionUpgradeFailed)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "RolledBack" {
+		t.Errorf("expected the UpgradeFailed condition to be rolled back, got %v", condition)
+	}
+	if failedImage := clusterRegistrar.GetAnnotations()[FailedUpgradeImageAnnotation]; failedImage != "compute-operator:v2" {
+		t.Errorf("expected the failed upgrade image compute-operator:v2, got %s", failedImage)
+	}
+	if isUpgradeInProgress(clusterRegistrar) {
+		t.Errorf("expected the upgrade to be over, got the annotations %v", clusterRegistrar.GetAnnotations())
+	}
+}
+
+func TestUpgradeOperatorInProgress(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(testScheme)
+	_ = singaporev1alpha1.AddToScheme(testScheme)
+
+	clusterRegistrar := &singaporev1alpha1.ClusterRegistrar{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-registrar"},
+	}
+	r := &ClusterRegistrarReconciler{
+		Client:                   clientfake.NewClientBuilder().WithScheme(testScheme).WithObjects(clusterRegistrar).Build(),
+		KubeClient:               kubefake.NewSimpleClientset(),
+		Log:                      logr.Discard(),
+		ControllerNamespace:      "compute-config",
+		ControllerImage:          "compute-operator:v2",
+		RollbackOnUpgradeFailure: true,
+	}
+	applier := apply.NewApplierBuilder().
+		WithClient(r.KubeClient, apiextensionsfake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(testScheme)).
+		Build()
+	readerDeploy := deploy.GetScenarioResourcesReader()
+
+	values := r.getInstallerValues(clusterRegistrar)
+	if _, err := applier.ApplyDeployments(readerDeploy, values, false, "", operatorDeploymentFile); err != nil {
+		t.Fatal(err)
+	}
+	objects, err := renderObjects(values, operatorDeploymentFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := objects[0].GetName()
+	if err := r.startUpgrade(context.TODO(), clusterRegistrar, "compute-operator:v1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rollout is not waited for within the UpgradeTimeout, the reconcile is requeued instead
+	if err := r.upgradeOperator(context.TODO(), clusterRegistrar, applier, readerDeploy, name); err != nil {
+		t.Fatal(err)
+	}
+	image, err := r.getDeploymentImage(context.TODO(), name, operatorContainerName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image != "compute-operator:v2" {
+		t.Errorf("expected the deployment to keep compute-operator:v2, got %s", image)
+	}
+	if !isUpgradeInProgress(clusterRegistrar) {
+		t.Errorf("expected the upgrade to be in progress, got the annotations %v", clusterRegistrar.GetAnnotations())
+	}
+	if previousImage := clusterRegistrar.GetAnnotations()[UpgradePreviousImageAnnotation]; previousImage != "compute-operator:v1" {
+		t.Errorf("expected the previous image compute-operator:v1, got %s", previousImage)
+	}
+	if condition := meta.FindStatusCondition(clusterRegistrar.Status.Conditions,
+		singaporev1alpha1.ClusterRegistrarConditionUpgradeFailed); condition != nil {
+		t.Errorf("expected no UpgradeFailed condition, got %v", condition)
+	}
+}
+
+func TestCreateOrUpdateClusterRoleDrift(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(testScheme)
+	_ = singaporev1alpha1.AddToScheme(testScheme)
+
+	clusterRegistrar := &singaporev1alpha1.ClusterRegistrar{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-registrar"},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &ClusterRegistrarReconciler{
+		Client:              clientfake.NewClientBuilder().WithScheme(testScheme).Build(),
+		Log:                 logr.Discard(),
+		Recorder:            recorder,
+		ControllerNamespace: "compute-config",
+	}
+	desired := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute-operator-manager-role"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list", "watch"}},
+		},
+	}
+
+	if err := r.createOrUpdateClusterRole(context.TODO(), clusterRegistrar, desired.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.createOrUpdateClusterRole(context.TODO(), clusterRegistrar, desired.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected no event without drift, got %s", <-recorder.Events)
+	}
+
+	// An admin removes a rule
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(desired), clusterRole); err != nil {
+		t.Fatal(err)
+	}
+	clusterRole.Rules = nil
+	if err := r.Client.Update(context.TODO(), clusterRole); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.createOrUpdateClusterRole(context.TODO(), clusterRegistrar, desired.DeepCopy()); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(desired), clusterRole); err != nil {
+		t.Fatal(err)
+	}
+	if !equality.Semantic.DeepEqual(clusterRole.Rules, desired.Rules) {
+		t.Errorf("expected the rules restored, got %v", clusterRole.Rules)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "ClusterRoleDriftCorrected") {
+			t.Errorf("expected a ClusterRoleDriftCorrected event, got %s", event)
+		}
+	default:
+		t.Error("expected a ClusterRoleDriftCorrected event")
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+)
+
+// GetDeploymentCondition returns a condition of the given type reflecting the Available condition of the
+// Deployment, a nil Deployment is not found.
+func GetDeploymentCondition(conditionType string, deployment *appsv1.Deployment) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   conditionType,
+		Status: metav1.ConditionFalse,
+	}
+	if deployment == nil {
+		condition.Reason = "DeploymentNotFound"
+		condition.Message = "The deployment is not found"
+		return condition
+	}
+	for _, deploymentCondition := range deployment.Status.Conditions {
+		if deploymentCondition.Type != appsv1.DeploymentAvailable {
+			continue
+		}
+		if deploymentCondition.Status == corev1.ConditionTrue && deployment.Status.AvailableReplicas > 0 {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "DeploymentAvailable"
+			condition.Message = fmt.Sprintf("The deployment %s/%s has %d available replicas",
+				deployment.Namespace, deployment.Name, deployment.Status.AvailableReplicas)
+			return condition
+		}
+		condition.Reason = "DeploymentNotAvailable"
+		condition.Message = fmt.Sprintf("The deployment %s/%s is not available: %s",
+			deployment.Namespace, deployment.Name, deploymentCondition.Message)
+		return condition
+	}
+	condition.Reason = "DeploymentNotAvailable"
+	condition.Message = fmt.Sprintf("The deployment %s/%s is not yet available", deployment.Namespace, deployment.Name)
+	return condition
+}
+
+// GetAPIServiceCondition returns a condition of the given type reflecting the Available condition of the
+// APIService, a nil APIService is not found.
+func GetAPIServiceCondition(conditionType string, apiService *apiregistrationv1.APIService) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   conditionType,
+		Status: metav1.ConditionFalse,
+	}
+	if apiService == nil {
+		condition.Reason = "APIServiceNotFound"
+		condition.Message = "The APIService is not found"
+		return condition
+	}
+	for _, apiServiceCondition := range apiService.Status.Conditions {
+		if apiServiceCondition.Type != apiregistrationv1.Available {
+			continue
+		}
+		if apiServiceCondition.Status == apiregistrationv1.ConditionTrue {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "APIServiceAvailable"
+			condition.Message = fmt.Sprintf("The APIService %s is available", apiService.Name)
+			return condition
+		}
+		condition.Reason = "APIServiceNotAvailable"
+		condition.Message = fmt.Sprintf("The APIService %s is not available: %s", apiService.Name, apiServiceCondition.Message)
+		return condition
+	}
+	condition.Reason = "APIServiceNotAvailable"
+	condition.Message = fmt.Sprintf("The APIService %s is not yet available", apiService.Name)
+	return condition
+}
+
+// IsDeploymentRolledOut returns true if the latest spec of the Deployment is observed and all its replicas are
+// updated and available, the replicas of the previous ReplicaSets being gone.
+func IsDeploymentRolledOut(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
@@ -0,0 +1,137 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+)
+
+func TestGetDeploymentCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		status     metav1.ConditionStatus
+		reason     string
+	}{
+		{name: "not found", status: metav1.ConditionFalse, reason: "DeploymentNotFound"},
+		{name: "no condition", deployment: &appsv1.Deployment{}, status: metav1.ConditionFalse, reason: "DeploymentNotAvailable"},
+		{
+			name: "not available",
+			deployment: &appsv1.Deployment{Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse}},
+			}},
+			status: metav1.ConditionFalse,
+			reason: "DeploymentNotAvailable",
+		},
+		{
+			name: "available",
+			deployment: &appsv1.Deployment{Status: appsv1.DeploymentStatus{
+				AvailableReplicas: 1,
+				Conditions:        []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+			}},
+			status: metav1.ConditionTrue,
+			reason: "DeploymentAvailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := GetDeploymentCondition("Deployed", tt.deployment)
+			if condition.Type != "Deployed" || condition.Status != tt.status || condition.Reason != tt.reason {
+				t.Errorf("expected %s/%s, got %s/%s", tt.status, tt.reason, condition.Status, condition.Reason)
+			}
+		})
+	}
+}
+
+func TestGetAPIServiceCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiService *apiregistrationv1.APIService
+		status     metav1.ConditionStatus
+		reason     string
+	}{
+		{name: "not found", status: metav1.ConditionFalse, reason: "APIServiceNotFound"},
+		{name: "no condition", apiService: &apiregistrationv1.APIService{}, status: metav1.ConditionFalse, reason: "APIServiceNotAvailable"},
+		{
+			name: "not available",
+			apiService: &apiregistrationv1.APIService{Status: apiregistrationv1.APIServiceStatus{
+				Conditions: []apiregistrationv1.APIServiceCondition{{Type: apiregistrationv1.Available, Status: apiregistrationv1.ConditionFalse}},
+			}},
+			status: metav1.ConditionFalse,
+			reason: "APIServiceNotAvailable",
+		},
+		{
+			name: "available",
+			apiService: &apiregistrationv1.APIService{Status: apiregistrationv1.APIServiceStatus{
+				Conditions: []apiregistrationv1.APIServiceCondition{{Type: apiregistrationv1.Available, Status: apiregistrationv1.ConditionTrue}},
+			}},
+			status: metav1.ConditionTrue,
+			reason: "APIServiceAvailable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := GetAPIServiceCondition("Available", tt.apiService)
+			if condition.Type != "Available" || condition.Status != tt.status || condition.Reason != tt.reason {
+				t.Errorf("expected %s/%s, got %s/%s", tt.status, tt.reason, condition.Status, condition.Reason)
+			}
+		})
+	}
+}
+
+func TestIsDeploymentRolledOut(t *testing.T) {
+	two := int32(2)
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		expected   bool
+	}{
+		{
+			name: "generation not observed",
+			deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			},
+		},
+		{
+			name: "new replica not available",
+			deployment: &appsv1.Deployment{
+				Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1},
+			},
+		},
+		{
+			name: "old replica not terminated",
+			deployment: &appsv1.Deployment{
+				Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 2},
+			},
+		},
+		{
+			name: "rolled out with default replicas",
+			deployment: &appsv1.Deployment{
+				Status: appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1},
+			},
+			expected: true,
+		},
+		{
+			name: "rolled out",
+			deployment: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec:       appsv1.DeploymentSpec{Replicas: &two},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rolledOut := IsDeploymentRolledOut(tt.deployment); rolledOut != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, rolledOut)
+			}
+		})
+	}
+}
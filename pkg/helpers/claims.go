@@ -0,0 +1,36 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// MergeClusterClaims returns the claims reported by the agent completed with the initial claims
+// not yet reported. A reported claim takes precedence over an initial claim with the same name.
+func MergeClusterClaims(reported []clusterapiv1.ManagedClusterClaim, initial []clusterapiv1.ManagedClusterClaim) []clusterapiv1.ManagedClusterClaim {
+	merged := append([]clusterapiv1.ManagedClusterClaim{}, reported...)
+	reportedNames := map[string]bool{}
+	for _, claim := range reported {
+		reportedNames[claim.Name] = true
+	}
+	for _, claim := range initial {
+		if !reportedNames[claim.Name] {
+			merged = append(merged, claim)
+		}
+	}
+	return merged
+}
+
+// ClusterIDClaimName is the name of the cluster claim holding the unique identifier of a cluster.
+const ClusterIDClaimName = "id.k8s.io"
+
+// GetClusterClaimValue returns the value of the cluster claim with the given name and whether it was found.
+func GetClusterClaimValue(claims []clusterapiv1.ManagedClusterClaim, name string) (string, bool) {
+	for _, claim := range claims {
+		if claim.Name == name {
+			return claim.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,49 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestMergeClusterClaims(t *testing.T) {
+	reported := []clusterapiv1.ManagedClusterClaim{
+		{Name: "id.k8s.io", Value: "cluster-id"},
+		{Name: "region.open-cluster-management.io", Value: "us-east-1"},
+	}
+	initial := []clusterapiv1.ManagedClusterClaim{
+		{Name: "region.open-cluster-management.io", Value: "eu-west-1"},
+		{Name: "routing.example.com", Value: "edge"},
+	}
+	merged := MergeClusterClaims(reported, initial)
+	if len(merged) != 3 {
+		t.Fatalf(`Number of claims not as expected. Expected 3, actual %d`, len(merged))
+	}
+	for _, claim := range merged {
+		if claim.Name == "region.open-cluster-management.io" && claim.Value != "us-east-1" {
+			t.Fatalf(`Reported claim not kept. Expected us-east-1, actual %s`, claim.Value)
+		}
+	}
+	if merged[2].Name != "routing.example.com" || merged[2].Value != "edge" {
+		t.Fatalf(`Initial claim not merged, actual %v`, merged[2])
+	}
+}
+
+func TestGetClusterClaimValue(t *testing.T) {
+	claims := []clusterapiv1.ManagedClusterClaim{
+		{Name: "region.open-cluster-management.io", Value: "us-east-1"},
+		{Name: ClusterIDClaimName, Value: "cluster-id"},
+	}
+	value, ok := GetClusterClaimValue(claims, ClusterIDClaimName)
+	if !ok || value != "cluster-id" {
+		t.Fatalf(`Claim value not as expected. Expected cluster-id, actual %s (found %t)`, value, ok)
+	}
+	if _, ok := GetClusterClaimValue(claims, "kubeversion.open-cluster-management.io"); ok {
+		t.Fatalf("Claim found but expected to be not found.")
+	}
+	if _, ok := GetClusterClaimValue(nil, ClusterIDClaimName); ok {
+		t.Fatalf("Claim found in nil claims but expected to be not found.")
+	}
+}
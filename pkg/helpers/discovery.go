@@ -0,0 +1,109 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// IsResourceServed returns true if the resource is served by the apiserver.
+// The discovery request is made with the context, so a cluster aware client discovers the
+// resources of the kcp workspace of the context, which depend on the workspace APIBindings.
+func IsResourceServed(ctx context.Context, discoveryClient discovery.DiscoveryInterface, gvr schema.GroupVersionResource) (bool, error) {
+	data, err := discoveryClient.RESTClient().Get().
+		AbsPath("/apis", gvr.Group, gvr.Version).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	resourceList := &metav1.APIResourceList{}
+	if err := json.Unmarshal(data, resourceList); err != nil {
+		return false, fmt.Errorf("failed to decode the %s resources: %w", gvr.GroupVersion(), err)
+	}
+	for _, resource := range resourceList.APIResources {
+		if resource.Name == gvr.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resourceServedCachePeriod is the period the discovery of a resource in a workspace is reused, a workspace binding
+// the resource later is discovered after that period.
+const resourceServedCachePeriod = time.Minute
+
+// ResourceServedCache caches the discovery of the resources per workspace, so the resources are not discovered on
+// every reconcile. The zero value is ready to use.
+type ResourceServedCache struct {
+	mutex  sync.Mutex
+	served map[string]cachedResourceServed
+}
+
+type cachedResourceServed struct {
+	served       bool
+	discoveredAt time.Time
+}
+
+// IsResourceServed returns true if the resource is served in the workspace, discovered with the context holding the
+// workspace if it is not cached or the cached result is older than resourceServedCachePeriod. Errors are not cached.
+func (c *ResourceServedCache) IsResourceServed(ctx context.Context, discoveryClient discovery.DiscoveryInterface,
+	workspace string, gvr schema.GroupVersionResource) (bool, error) {
+	key := workspace + "/" + gvr.String()
+	c.mutex.Lock()
+	cached, ok := c.served[key]
+	c.mutex.Unlock()
+	if ok && time.Since(cached.discoveredAt) < resourceServedCachePeriod {
+		return cached.served, nil
+	}
+	served, err := IsResourceServed(ctx, discoveryClient, gvr)
+	if err != nil {
+		return false, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.served == nil {
+		c.served = map[string]cachedResourceServed{}
+	}
+	c.served[key] = cachedResourceServed{served: served, discoveredAt: time.Now()}
+	return served, nil
+}
+
+// IsResourceNotFound returns true if the error reports that the resource itself is not served, and not that an
+// object of the resource is not found. A client with a REST mapper returns a NoKindMatch error, the apiserver
+// returns a NotFound error without object details or built from a non-status response, as for a kcp workspace
+// without the APIBinding of the resource.
+func IsResourceNotFound(err error) bool {
+	if meta.IsNoMatchError(err) {
+		return true
+	}
+	if !k8serrors.IsNotFound(err) {
+		return false
+	}
+	if status, ok := err.(k8serrors.APIStatus); ok {
+		details := status.Status().Details
+		if details == nil || len(details.Name) == 0 {
+			return true
+		}
+		for _, cause := range details.Causes {
+			if cause.Type == metav1.CauseTypeUnexpectedServerResponse {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
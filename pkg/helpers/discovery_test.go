@@ -0,0 +1,130 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+func TestIsResourceServed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/apis/singapore.open-cluster-management.io/v1alpha1" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"singapore.open-cluster-management.io/v1alpha1",` +
+			`"resources":[{"name":"registeredclusters","namespaced":true,"kind":"RegisteredCluster","verbs":["get"]}]}`))
+	}))
+	defer server.Close()
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	gvr := schema.GroupVersionResource{Group: "singapore.open-cluster-management.io", Version: "v1alpha1", Resource: "registeredclusters"}
+	served, err := IsResourceServed(context.TODO(), discoveryClient, gvr)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !served {
+		t.Fatalf(`Expected %s to be served`, gvr)
+	}
+
+	gvr.Resource = "hubconfigs"
+	if served, err = IsResourceServed(context.TODO(), discoveryClient, gvr); err != nil || served {
+		t.Fatalf(`Expected %s to not be served, actual served %t, error %v`, gvr, served, err)
+	}
+
+	gvr.Version = "v1beta1"
+	if served, err = IsResourceServed(context.TODO(), discoveryClient, gvr); err != nil || served {
+		t.Fatalf(`Expected %s to not be served, actual served %t, error %v`, gvr, served, err)
+	}
+}
+
+func TestResourceServedCache(t *testing.T) {
+	discoveryCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/apis/singapore.open-cluster-management.io/v1alpha1" {
+			http.NotFound(w, req)
+			return
+		}
+		discoveryCalls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"APIResourceList","apiVersion":"v1","groupVersion":"singapore.open-cluster-management.io/v1alpha1",` +
+			`"resources":[{"name":"registeredclusters","namespaced":true,"kind":"RegisteredCluster","verbs":["get"]}]}`))
+	}))
+	defer server.Close()
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	gvr := schema.GroupVersionResource{Group: "singapore.open-cluster-management.io", Version: "v1alpha1", Resource: "registeredclusters"}
+	cache := &ResourceServedCache{}
+	for _, workspace := range []string{"root:org:location1", "root:org:location1", "root:org:location2"} {
+		served, err := cache.IsResourceServed(context.TODO(), discoveryClient, workspace, gvr)
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		if !served {
+			t.Fatalf(`Expected %s to be served in %s`, gvr, workspace)
+		}
+	}
+	if discoveryCalls != 2 {
+		t.Fatalf(`Discovery requests not as expected. Expected 2, actual %d`, discoveryCalls)
+	}
+}
+
+func TestIsResourceNotFound(t *testing.T) {
+	gr := schema.GroupResource{Group: "singapore.open-cluster-management.io", Resource: "registeredclusters"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "object not found",
+			err:  k8serrors.NewNotFound(gr, "cluster1"),
+			want: false,
+		},
+		{
+			name: "resource not found",
+			err:  k8serrors.NewNotFound(gr, ""),
+			want: true,
+		},
+		{
+			name: "unexpected not found response",
+			err:  k8serrors.NewGenericServerResponse(404, "get", gr, "cluster1", "404 page not found", 0, true),
+			want: true,
+		},
+		{
+			name: "no kind match",
+			err:  &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: gr.Group, Kind: "RegisteredCluster"}},
+			want: true,
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsResourceNotFound(tt.err); got != tt.want {
+				t.Fatalf("IsResourceNotFound not as expected. Expected %t, actual %t", tt.want, got)
+			}
+		})
+	}
+}
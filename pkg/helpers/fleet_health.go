@@ -0,0 +1,32 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// The fleet health states of a RegisteredCluster.
+const (
+	// FleetHealthImporting is the state of a RegisteredCluster whose ManagedCluster has not joined yet.
+	FleetHealthImporting = "importing"
+	// FleetHealthHealthy is the state of a RegisteredCluster whose ManagedCluster joined and is available.
+	FleetHealthHealthy = "healthy"
+	// FleetHealthDegraded is the state of a RegisteredCluster whose ManagedCluster joined but is not available.
+	FleetHealthDegraded = "degraded"
+)
+
+// FleetHealthStates are all the fleet health states.
+var FleetHealthStates = []string{FleetHealthImporting, FleetHealthHealthy, FleetHealthDegraded}
+
+// GetFleetHealthState classifies a RegisteredCluster from the ManagedCluster conditions copied in its status.
+func GetFleetHealthState(conditions []metav1.Condition) string {
+	if status, ok := GetConditionStatus(conditions, clusterapiv1.ManagedClusterConditionJoined); !ok || status != metav1.ConditionTrue {
+		return FleetHealthImporting
+	}
+	if status, ok := GetConditionStatus(conditions, clusterapiv1.ManagedClusterConditionAvailable); ok && status == metav1.ConditionTrue {
+		return FleetHealthHealthy
+	}
+	return FleetHealthDegraded
+}
@@ -0,0 +1,51 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestGetFleetHealthState(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		state      string
+	}{
+		{
+			name:  "no condition",
+			state: FleetHealthImporting,
+		},
+		{
+			name: "not joined",
+			conditions: []metav1.Condition{
+				{Type: clusterapiv1.ManagedClusterConditionJoined, Status: metav1.ConditionFalse},
+			},
+			state: FleetHealthImporting,
+		},
+		{
+			name: "joined and available",
+			conditions: []metav1.Condition{
+				{Type: clusterapiv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue},
+				{Type: clusterapiv1.ManagedClusterConditionAvailable, Status: metav1.ConditionTrue},
+			},
+			state: FleetHealthHealthy,
+		},
+		{
+			name: "joined and unknown availability",
+			conditions: []metav1.Condition{
+				{Type: clusterapiv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue},
+				{Type: clusterapiv1.ManagedClusterConditionAvailable, Status: metav1.ConditionUnknown},
+			},
+			state: FleetHealthDegraded,
+		},
+	}
+	for _, test := range tests {
+		if state := GetFleetHealthState(test.conditions); state != test.state {
+			t.Fatalf(`%s: state not as expected. Expected %s, actual %s`, test.name, test.state, state)
+		}
+	}
+}
@@ -9,4 +9,8 @@ var (
 		Group:    "singapore.open-cluster-management.io",
 		Version:  "v1alpha1",
 		Resource: "clusterregistrars"}
+	GvrRC schema.GroupVersionResource = schema.GroupVersionResource{
+		Group:    "singapore.open-cluster-management.io",
+		Version:  "v1alpha1",
+		Resource: "registeredclusters"}
 )
@@ -5,11 +5,15 @@ package helpers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/stolostron/applier/pkg/apply"
 	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -18,17 +22,114 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 )
 
+// The timeout to check that a hub is reachable when loading its HubConfig
+const hubReachableTimeout = 10 * time.Second
+
+// The period during which a successful hub reachability check is not repeated
+const hubReachableCachePeriod = 30 * time.Second
+
+var hubConfigGVR = schema.GroupVersionResource{
+	Group:    "singapore.open-cluster-management.io",
+	Version:  "v1alpha1",
+	Resource: "hubconfigs"}
+
 type HubInstance struct {
 	HubConfig      *singaporev1alpha1.HubConfig
 	Cluster        cluster.Cluster
 	Client         client.Client
 	ApplierBuilder *apply.ApplierBuilder
+	// KubeClient is used to check that the hub is reachable
+	KubeClient kubernetes.Interface
+
+	reachability *hubReachability
+	// cluster runs the cache of the hub, it is stopped when the hub instance is replaced
+	cluster *stoppableCluster
+}
+
+// Stop stops the cache of the hub instance, and so the watches using it, without stopping the manager. It is
+// called when the hub instance is replaced by a new one.
+func (h HubInstance) Stop() {
+	if h.cluster != nil {
+		h.cluster.stop()
+	}
+}
+
+// stoppableCluster is a hub cluster added to the manager which can be stopped before the manager.
+type stoppableCluster struct {
+	cluster.Cluster
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// Start runs the cluster until the manager stops or the cluster is stopped.
+func (c *stoppableCluster) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return c.Cluster.Start(ctx)
+}
+
+func (c *stoppableCluster) stop() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+}
+
+// hubReachability is the last successful reachability check of a hub, shared by the copies of its HubInstance.
+type hubReachability struct {
+	mutex       sync.Mutex
+	lastChecked time.Time
+}
+
+// Validate checks that the hub instance is usable by the reconcile, all its clients are set and the hub
+// is reachable. A successful reachability check is cached for a short period.
+func (h HubInstance) Validate(ctx context.Context) error {
+	if h.HubConfig == nil {
+		return errors.New("hub instance has no HubConfig")
+	}
+	switch {
+	case h.Cluster == nil:
+		return fmt.Errorf("hub %s has no cluster cache", h.HubConfig.Name)
+	case h.Client == nil:
+		return fmt.Errorf("hub %s has no client", h.HubConfig.Name)
+	case h.ApplierBuilder == nil:
+		return fmt.Errorf("hub %s has no applier builder", h.HubConfig.Name)
+	case h.KubeClient == nil:
+		return fmt.Errorf("hub %s has no kube client", h.HubConfig.Name)
+	}
+
+	if h.reachability != nil {
+		h.reachability.mutex.Lock()
+		defer h.reachability.mutex.Unlock()
+		if time.Since(h.reachability.lastChecked) < hubReachableCachePeriod {
+			return nil
+		}
+	}
+	if err := h.Ping(ctx); err != nil {
+		return fmt.Errorf("hub %s is unreachable: %w", h.HubConfig.Name, err)
+	}
+	if h.reachability != nil {
+		h.reachability.lastChecked = time.Now()
+	}
+	return nil
+}
+
+// Ping checks that the hub API is reachable and accepts the hub credentials, the check is not cached.
+func (h HubInstance) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, hubReachableTimeout)
+	defer cancel()
+	return h.KubeClient.Discovery().RESTClient().Get().AbsPath("/healthz").Do(ctx).Error()
 }
 
 // GetConditionStatus returns the status for a given condition type and whether the condition was found
@@ -46,51 +147,194 @@ func GetConditionStatus(conditions []metav1.Condition, t string) (status metav1.
 	return "", false
 }
 
-func GetHubCluster(workspace string, hubInstances []HubInstance) (HubInstance, error) {
-	// For now, we always assume there is only one hub cluster. //TODO Later we will replace this with a lookup.
+// HubAnnotation is the RegisteredCluster annotation pinning it to the HubConfig with the given name.
+const HubAnnotation string = "singapore.open-cluster-management.io/hub"
+
+// GetHubCluster returns the hub instance for a RegisteredCluster. The HubConfig named by the HubAnnotation
+// takes precedence, if it is not set or does not match any hub, the hub is resolved from the workspace.
+func GetHubCluster(workspace string, annotations map[string]string, hubInstances []HubInstance) (HubInstance, error) {
 	if len(hubInstances) == 0 {
 		return HubInstance{}, errors.New("hub cluster is not configured")
 	}
+	if hubName := annotations[HubAnnotation]; len(hubName) != 0 {
+		for _, hubInstance := range hubInstances {
+			if hubInstance.HubConfig.Name == hubName {
+				return hubInstance, nil
+			}
+		}
+		ctrl.Log.WithName("GetHubCluster").Info("hub from annotation not found, falling back to the workspace hub",
+			"annotation", HubAnnotation,
+			"hub", hubName,
+			"workspace", workspace)
+	}
+	// For now, we always assume there is only one hub cluster. //TODO Later we will replace this with a lookup.
 	return hubInstances[0], nil
 }
 
+// GetHubClusters returns the hub instances of the valid HubConfigs of the operator namespace.
+// The HubConfigs failing the validation are skipped.
 func GetHubClusters(ctx context.Context, mgr ctrl.Manager, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface) ([]HubInstance, error) {
+	hubInstances, nbHubConfigs, err := getHubClusters(ctx, mgr, kubeClient, dynamicClient, nil)
+	if err != nil {
+		return nil, err
+	}
+	if nbHubConfigs != 0 && len(hubInstances) == 0 {
+		return nil, errors.New("none of the HubConfigs is valid")
+	}
+	return hubInstances, nil
+}
+
+// GetNewHubClusters returns the hub instances of the valid HubConfigs which are not in the given hub instances.
+// It is used to add the hubs of the HubConfigs created after the operator start.
+func GetNewHubClusters(ctx context.Context, mgr ctrl.Manager, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
+	knownHubInstances []HubInstance) ([]HubInstance, error) {
+	known := make(map[string]bool, len(knownHubInstances))
+	for _, hubInstance := range knownHubInstances {
+		known[hubInstance.HubConfig.Name] = true
+	}
+	hubInstances, _, err := getHubClusters(ctx, mgr, kubeClient, dynamicClient, known)
+	return hubInstances, err
+}
+
+// getHubClusters returns the hub instances of the valid HubConfigs not in known and the number of HubConfigs
+// not in known.
+func getHubClusters(ctx context.Context, mgr ctrl.Manager, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
+	known map[string]bool) ([]HubInstance, int, error) {
 	setupLog := ctrl.Log.WithName("setup")
 	hubInstances := make([]HubInstance, 0)
 	setupLog.Info("retrieve POD namespace")
 	namespace := os.Getenv("POD_NAMESPACE")
 	if len(namespace) == 0 {
 		err := errors.New("POD_NAMESPACE not defined")
-		return nil, err
+		return nil, 0, err
 	}
 
-	gvr := schema.GroupVersionResource{
-		Group:    "singapore.open-cluster-management.io",
-		Version:  "v1alpha1",
-		Resource: "hubconfigs"}
-
 	setupLog.Info("retrieve list of hubConfig")
-	hubConfigListU, err := dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	hubConfigListU, err := dynamicClient.Resource(hubConfigGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	setupLog.Info("nb of hubConfig unstructured found", "sze", len(hubConfigListU.Items))
 
+	nbHubConfigs := 0
 	for _, hubConfigU := range hubConfigListU.Items {
+		if known[hubConfigU.GetName()] {
+			continue
+		}
+		nbHubConfigs++
 
-		kubeConfigData, hubConfig, err := getKubeConfigDataFromHubConfig(ctx, hubConfigU, kubeClient)
+		hubKubeconfig, hubConfig, reason, err := validateHubConfig(ctx, hubConfigU, kubeClient)
 		if err != nil {
-			return nil, err
+			setupLog.Error(err, "invalid HubConfig, skipping it", "HubConfig Name", hubConfigU.GetName(), "reason", reason)
+			updateHubConfigCondition(ctx, dynamicClient, hubConfigGVR, hubConfig, metav1.ConditionFalse, reason, err.Error())
+			continue
 		}
 
-		hubInstance, err := getHubInstance(kubeConfigData, mgr, hubConfig)
+		hubInstance, err := getHubInstance(hubKubeconfig, mgr, hubConfig)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		updateHubConfigCondition(ctx, dynamicClient, hubConfigGVR, hubConfig, metav1.ConditionTrue, "HubConfigValid", "The hub is reachable")
 
 		hubInstances = append(hubInstances, *hubInstance)
 	}
-	return hubInstances, nil
+	return hubInstances, nbHubConfigs, nil
+}
+
+// ReloadHubCluster builds a new hub instance from the current kubeconfig secret of the HubConfig of the given hub,
+// to replace a hub whose credentials are no longer accepted. An error is returned if the HubConfig is still invalid.
+func ReloadHubCluster(ctx context.Context, mgr ctrl.Manager, kubeClient kubernetes.Interface, dynamicClient dynamic.Interface,
+	hubInstance HubInstance) (*HubInstance, error) {
+	hubConfigU, err := dynamicClient.Resource(hubConfigGVR).Namespace(hubInstance.HubConfig.Namespace).Get(ctx,
+		hubInstance.HubConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	hubKubeconfig, hubConfig, _, err := validateHubConfig(ctx, *hubConfigU, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	return getHubInstance(hubKubeconfig, mgr, hubConfig)
+}
+
+// SetHubConfigCondition sets a condition of a HubConfig. The HubConfig is read again to compare the condition
+// with its current status, the status is only written when the condition changes.
+func SetHubConfigCondition(ctx context.Context, dynamicClient dynamic.Interface, hubConfig *singaporev1alpha1.HubConfig,
+	condition metav1.Condition) error {
+	hubConfigU, err := dynamicClient.Resource(hubConfigGVR).Namespace(hubConfig.Namespace).Get(ctx, hubConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	currentHubConfig := &singaporev1alpha1.HubConfig{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(hubConfigU.Object, currentHubConfig); err != nil {
+		return err
+	}
+	if current := meta.FindStatusCondition(currentHubConfig.Status.Conditions, condition.Type); current != nil &&
+		current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message {
+		return nil
+	}
+	currentHubConfig.Status.Conditions = MergeStatusConditions(currentHubConfig.Status.Conditions, condition)
+	hubConfigObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(currentHubConfig)
+	if err != nil {
+		return err
+	}
+	_, err = dynamicClient.Resource(hubConfigGVR).Namespace(hubConfig.Namespace).UpdateStatus(ctx,
+		&unstructured.Unstructured{Object: hubConfigObj}, metav1.UpdateOptions{})
+	return err
+}
+
+// validateHubConfig checks that the kubeconfig secret of a HubConfig exists, parses as a kubeconfig and
+// yields a client which can reach the hub. On failure, it returns the reason of the HubConfigValid condition.
+func validateHubConfig(ctx context.Context, hubConfigU unstructured.Unstructured,
+	kubeClient kubernetes.Interface) (*rest.Config, *singaporev1alpha1.HubConfig, string, error) {
+	kubeConfigData, hubConfig, err := getKubeConfigDataFromHubConfig(ctx, hubConfigU, kubeClient)
+	if err != nil {
+		return nil, hubConfig, "KubeconfigSecretInvalid", err
+	}
+
+	hubKubeconfig, err := getHubRestConfig(kubeConfigData, hubConfig)
+	if err != nil {
+		return nil, hubConfig, "KubeconfigInvalid", err
+	}
+
+	reachableConfig := rest.CopyConfig(hubKubeconfig)
+	reachableConfig.Timeout = hubReachableTimeout
+	hubKubeClient, err := kubernetes.NewForConfig(reachableConfig)
+	if err != nil {
+		return nil, hubConfig, "KubeconfigInvalid", err
+	}
+	if _, err := hubKubeClient.Discovery().ServerVersion(); err != nil {
+		return nil, hubConfig, "HubUnreachable", err
+	}
+	return hubKubeconfig, hubConfig, "", nil
+}
+
+// updateHubConfigCondition sets the HubConfigValid condition of a HubConfig, a failure is only logged
+// as the HubConfig status is informational.
+func updateHubConfigCondition(ctx context.Context, dynamicClient dynamic.Interface, gvr schema.GroupVersionResource,
+	hubConfig *singaporev1alpha1.HubConfig, status metav1.ConditionStatus, reason, message string) {
+	setupLog := ctrl.Log.WithName("setup")
+	// The HubConfigs are validated again when polled, only write the condition when it changes
+	if condition := meta.FindStatusCondition(hubConfig.Status.Conditions, singaporev1alpha1.HubConfigConditionValid); condition != nil &&
+		condition.Status == status && condition.Reason == reason && condition.Message == message {
+		return
+	}
+	hubConfig.Status.Conditions = MergeStatusConditions(hubConfig.Status.Conditions, metav1.Condition{
+		Type:    singaporev1alpha1.HubConfigConditionValid,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	hubConfigObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hubConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to convert HubConfig", "HubConfig Name", hubConfig.GetName())
+		return
+	}
+	if _, err := dynamicClient.Resource(gvr).Namespace(hubConfig.Namespace).UpdateStatus(ctx,
+		&unstructured.Unstructured{Object: hubConfigObj},
+		metav1.UpdateOptions{}); err != nil {
+		setupLog.Error(err, "unable to update HubConfig status", "HubConfig Name", hubConfig.GetName())
+	}
 }
 
 func getKubeConfigDataFromHubConfig(ctx context.Context, hubConfigU unstructured.Unstructured,
@@ -123,7 +367,7 @@ func getKubeConfigDataFromHubConfig(ctx context.Context, hubConfigU unstructured
 	return kubeConfigData, hubConfig, nil
 }
 
-func getHubInstance(kubeConfigData []byte, mgr ctrl.Manager, hubConfig *singaporev1alpha1.HubConfig) (*HubInstance, error) {
+func getHubRestConfig(kubeConfigData []byte, hubConfig *singaporev1alpha1.HubConfig) (*rest.Config, error) {
 	setupLog := ctrl.Log.WithName("setup")
 	setupLog.Info("generate hubKubeConfig")
 	hubKubeconfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfigData)
@@ -147,6 +391,11 @@ func getHubInstance(kubeConfigData []byte, mgr ctrl.Manager, hubConfig *singapor
 	if hubConfig.Spec.QPS == "" {
 		hubKubeconfig.QPS = 100.0
 	}
+	return hubKubeconfig, nil
+}
+
+func getHubInstance(hubKubeconfig *rest.Config, mgr ctrl.Manager, hubConfig *singaporev1alpha1.HubConfig) (*HubInstance, error) {
+	setupLog := ctrl.Log.WithName("setup")
 
 	// Add MCE cluster
 	hubCluster, err := cluster.New(hubKubeconfig,
@@ -161,8 +410,9 @@ func getHubInstance(kubeConfigData []byte, mgr ctrl.Manager, hubConfig *singapor
 		return nil, err
 	}
 
-	// Add MCE cluster to manager
-	if err := mgr.Add(hubCluster); err != nil {
+	// Add MCE cluster to manager, the cluster can be stopped when the hub instance is replaced
+	runnableCluster := &stoppableCluster{Cluster: hubCluster, stopCh: make(chan struct{})}
+	if err := mgr.Add(runnableCluster); err != nil {
 		setupLog.Error(err, "unable to add MCE cluster")
 		return nil, err
 	}
@@ -178,6 +428,9 @@ func getHubInstance(kubeConfigData []byte, mgr ctrl.Manager, hubConfig *singapor
 		Cluster:        hubCluster,
 		Client:         hubCluster.GetClient(),
 		ApplierBuilder: hubApplierBuilder,
+		KubeClient:     kubeClient,
+		reachability:   &hubReachability{},
+		cluster:        runnableCluster,
 	}
 	return &hubInstance, nil
 }
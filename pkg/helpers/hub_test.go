@@ -3,9 +3,26 @@
 package helpers
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/stolostron/applier/pkg/apply"
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 )
 
 func TestGetConditionStatusFound(t *testing.T) {
@@ -36,3 +53,226 @@ func TestGetConditionStatusNotFound(t *testing.T) {
 		t.Fatalf("Condition found but expected to be not found.")
 	}
 }
+
+func TestGetHubClusterFromAnnotation(t *testing.T) {
+	hubInstances := []HubInstance{
+		{HubConfig: &singaporev1alpha1.HubConfig{ObjectMeta: metav1.ObjectMeta{Name: "hub1"}}},
+		{HubConfig: &singaporev1alpha1.HubConfig{ObjectMeta: metav1.ObjectMeta{Name: "hub2"}}},
+	}
+	hubInstance, err := GetHubCluster("rc-ws", map[string]string{HubAnnotation: "hub2"}, hubInstances)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if hubInstance.HubConfig.Name != "hub2" {
+		t.Fatalf(`Hub not as expected. Expected hub2, actual %s`, hubInstance.HubConfig.Name)
+	}
+	hubInstance, err = GetHubCluster("rc-ws", map[string]string{HubAnnotation: "unknown"}, hubInstances)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if hubInstance.HubConfig.Name != "hub1" {
+		t.Fatalf(`Hub not as expected. Expected hub1, actual %s`, hubInstance.HubConfig.Name)
+	}
+}
+
+func TestGetHubClusterNotConfigured(t *testing.T) {
+	if _, err := GetHubCluster("rc-ws", nil, []HubInstance{}); err == nil {
+		t.Fatalf("Expected an error when no hub is configured")
+	}
+}
+
+func getTestHubConfig() unstructured.Unstructured {
+	return unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "singapore.open-cluster-management.io/v1alpha1",
+			"kind":       "HubConfig",
+			"metadata": map[string]interface{}{
+				"name":      "hub1",
+				"namespace": "compute-config",
+			},
+			"spec": map[string]interface{}{
+				"kubeconfigSecretRef": map[string]interface{}{
+					"name": "hub1-kubeconfig",
+				},
+			},
+		},
+	}
+}
+
+func TestValidateHubConfigSecretNotFound(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	_, _, reason, err := validateHubConfig(context.TODO(), getTestHubConfig(), kubeClient)
+	if err == nil {
+		t.Fatalf("Expected an error when the kubeconfig secret does not exist")
+	}
+	if reason != "KubeconfigSecretInvalid" {
+		t.Fatalf(`Reason not as expected. Expected KubeconfigSecretInvalid, actual %s`, reason)
+	}
+}
+
+func TestValidateHubConfigInvalidKubeconfig(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hub1-kubeconfig",
+			Namespace: "compute-config",
+		},
+		Data: map[string][]byte{
+			"kubeconfig": []byte("not a kubeconfig"),
+		},
+	})
+	_, hubConfig, reason, err := validateHubConfig(context.TODO(), getTestHubConfig(), kubeClient)
+	if err == nil {
+		t.Fatalf("Expected an error when the kubeconfig can not be parsed")
+	}
+	if reason != "KubeconfigInvalid" {
+		t.Fatalf(`Reason not as expected. Expected KubeconfigInvalid, actual %s`, reason)
+	}
+	if hubConfig.Name != "hub1" {
+		t.Fatalf(`HubConfig not as expected. Expected hub1, actual %s`, hubConfig.Name)
+	}
+}
+
+type testCluster struct {
+	cluster.Cluster
+}
+
+func TestHubInstanceValidate(t *testing.T) {
+	healthy := true
+	healthzCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/healthz" {
+			http.NotFound(w, req)
+			return
+		}
+		healthzCalls++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	kubeClient, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	hubInstance := HubInstance{
+		HubConfig:      &singaporev1alpha1.HubConfig{ObjectMeta: metav1.ObjectMeta{Name: "hub1"}},
+		Cluster:        testCluster{},
+		Client:         clientfake.NewClientBuilder().Build(),
+		ApplierBuilder: apply.NewApplierBuilder(),
+		KubeClient:     kubeClient,
+		reachability:   &hubReachability{},
+	}
+
+	invalidHubInstance := hubInstance
+	invalidHubInstance.ApplierBuilder = nil
+	if err := invalidHubInstance.Validate(context.TODO()); err == nil {
+		t.Fatalf("Expected an error for a hub without applier builder")
+	}
+
+	healthy = false
+	if err := hubInstance.Validate(context.TODO()); err == nil {
+		t.Fatalf("Expected an error for an unhealthy hub")
+	}
+
+	healthy = true
+	if err := hubInstance.Validate(context.TODO()); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	// The copies of the hub instance share the cached reachability
+	copiedHubInstance := hubInstance
+	if err := copiedHubInstance.Validate(context.TODO()); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if healthzCalls != 2 {
+		t.Fatalf(`Health checks not as expected. Expected 2, actual %d`, healthzCalls)
+	}
+}
+
+type blockingCluster struct {
+	cluster.Cluster
+}
+
+func (c blockingCluster) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestHubInstanceStop(t *testing.T) {
+	hubInstance := HubInstance{
+		HubConfig: &singaporev1alpha1.HubConfig{ObjectMeta: metav1.ObjectMeta{Name: "hub1"}},
+		cluster:   &stoppableCluster{Cluster: blockingCluster{}, stopCh: make(chan struct{})},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- hubInstance.cluster.Start(ctx)
+	}()
+
+	// A copy of the replaced hub instance stops the same cluster, stopping twice is fine
+	copiedHubInstance := hubInstance
+	copiedHubInstance.Stop()
+	hubInstance.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatalf("Expected the hub cluster to stop")
+	}
+}
+
+func TestSetHubConfigCondition(t *testing.T) {
+	hubConfig := &singaporev1alpha1.HubConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: singaporev1alpha1.SchemeGroupVersion.String(),
+			Kind:       "HubConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "hub1", Namespace: "compute-config"},
+	}
+	hubConfigObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hubConfig)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{hubConfigGVR: "HubConfigList"},
+		&unstructured.Unstructured{Object: hubConfigObj})
+
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.HubConfigConditionReachable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "HubUnreachable",
+		Message: "The hub is unreachable",
+	}
+	if err := SetHubConfigCondition(context.TODO(), dynamicClient, hubConfig, condition); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if err := SetHubConfigCondition(context.TODO(), dynamicClient, hubConfig, condition); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	updates := 0
+	for _, action := range dynamicClient.Actions() {
+		if action.GetVerb() == "update" {
+			updates++
+		}
+	}
+	if updates != 1 {
+		t.Fatalf(`Status updates not as expected. Expected 1, actual %d`, updates)
+	}
+
+	hubConfigU, err := dynamicClient.Resource(hubConfigGVR).Namespace("compute-config").Get(context.TODO(), "hub1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	updatedHubConfig := &singaporev1alpha1.HubConfig{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(hubConfigU.Object, updatedHubConfig); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if status, ok := GetConditionStatus(updatedHubConfig.Status.Conditions, singaporev1alpha1.HubConfigConditionReachable); !ok || status != metav1.ConditionFalse {
+		t.Fatalf(`HubReachable condition not as expected. Expected False, actual %s`, status)
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetDefaultImagePullPolicy returns the pull policy kubernetes defaults to for the image, Always for the latest
+// tag or an image without tag, else IfNotPresent. An image referenced by digest is immutable.
+func GetDefaultImagePullPolicy(image string) corev1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	// The tag is after the last colon, unless that colon is the registry port
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") || image[i+1:] == "latest" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
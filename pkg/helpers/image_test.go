@@ -0,0 +1,28 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetDefaultImagePullPolicy(t *testing.T) {
+	tests := []struct {
+		image  string
+		policy corev1.PullPolicy
+	}{
+		{image: "quay.io/stolostron/compute-operator:latest", policy: corev1.PullAlways},
+		{image: "quay.io/stolostron/compute-operator", policy: corev1.PullAlways},
+		{image: "localhost:5000/compute-operator", policy: corev1.PullAlways},
+		{image: "quay.io/stolostron/compute-operator:v0.1.0", policy: corev1.PullIfNotPresent},
+		{image: "localhost:5000/compute-operator:v0.1.0", policy: corev1.PullIfNotPresent},
+		{image: "quay.io/stolostron/compute-operator@sha256:0123456789abcdef", policy: corev1.PullIfNotPresent},
+	}
+	for _, tt := range tests {
+		if policy := GetDefaultImagePullPolicy(tt.image); policy != tt.policy {
+			t.Errorf("image %s: expected %s, got %s", tt.image, tt.policy, policy)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultImportCommandTemplate is the default template of the import command, a bash one-liner.
+// The base64 payloads are single quoted and the two applies are separated by a pause, letting the
+// crds be established before the import resources are applied.
+const DefaultImportCommandTemplate = `echo {{ quote .CRDs }} | base64 --decode | {{ .CLI }} apply -f - && sleep {{ .Sleep }} && ` +
+	`echo {{ quote .Import }} | base64 --decode | {{ .CLI }} apply -f -`
+
+// DefaultImportCommandCLI is the default command line applying the import resources.
+const DefaultImportCommandCLI = "kubectl"
+
+// DefaultImportCommandSleep is the default pause between the crds and the import resources applies.
+const DefaultImportCommandSleep = 2 * time.Second
+
+// importCommandFuncs are the functions available in the import command template
+var importCommandFuncs = template.FuncMap{
+	"quote": ShellQuote,
+}
+
+// ParseImportCommandTemplate parses an import command template. Besides the template builtins,
+// the quote function shell quotes its argument.
+func ParseImportCommandTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("import-command").Option("missingkey=error").Funcs(importCommandFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import command template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// ShellQuote returns s single quoted for a POSIX shell, the single quotes of s are escaped.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// BuildImportCommand renders the import command template with the base64 encoded crds and import data,
+// the command line applying them, for example kubectl or oc, and the pause between the two applies.
+// The payloads are always rendered as canonical base64, whatever the import secret data holds.
+func BuildImportCommand(tmpl *template.Template, cli string, crds, importData []byte, sleep time.Duration) (string, error) {
+	encodedCRDs := EncodeImportData(crds)
+	encodedImport := EncodeImportData(importData)
+	if len(encodedCRDs) == 0 || len(encodedImport) == 0 {
+		return "", errors.New("missing crds or import data")
+	}
+	if sleep < 0 {
+		return "", fmt.Errorf("invalid import command sleep %s", sleep)
+	}
+	var command bytes.Buffer
+	if err := tmpl.Execute(&command, map[string]string{
+		"CLI":    cli,
+		"CRDs":   encodedCRDs,
+		"Import": encodedImport,
+		"Sleep":  strconv.FormatFloat(sleep.Seconds(), 'f', -1, 64),
+	}); err != nil {
+		return "", fmt.Errorf("failed to render the import command: %w", err)
+	}
+	return command.String(), nil
+}
+
+// The keys of the data of the import secret generated by the hub, the crds key depends on the MCE version.
+const (
+	ImportSecretImportKey = "import.yaml"
+	ImportSecretCRDsV1Key = "crdsv1.yaml"
+	ImportSecretCRDsKey   = "crds.yaml"
+)
+
+// importSecretCRDsKeys are the keys holding the crds in the import secret, by order of preference
+var importSecretCRDsKeys = []string{ImportSecretCRDsV1Key, ImportSecretCRDsKey}
+
+// GetImportSecretData returns the crds and import data of the import secret generated by the hub, the crds are
+// read from the first of importSecretCRDsKeys found. An error listing the keys of the secret is returned if the
+// crds or the import data are missing or empty.
+func GetImportSecretData(secret *corev1.Secret) (crds, importData []byte, err error) {
+	var missing []string
+	for _, key := range importSecretCRDsKeys {
+		if crds = secret.Data[key]; len(bytes.TrimSpace(crds)) != 0 {
+			break
+		}
+	}
+	if len(bytes.TrimSpace(crds)) == 0 {
+		missing = append(missing, strings.Join(importSecretCRDsKeys, " or "))
+	}
+	importData = secret.Data[ImportSecretImportKey]
+	if len(bytes.TrimSpace(importData)) == 0 {
+		missing = append(missing, ImportSecretImportKey)
+	}
+	if len(missing) == 0 {
+		return crds, importData, nil
+	}
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return nil, nil, fmt.Errorf("import secret %s/%s is missing %s, found keys [%s]",
+		secret.Namespace, secret.Name, strings.Join(missing, " and "), strings.Join(keys, ", "))
+}
+
+// The secret of the import.yaml holding the kubeconfig used by the agent to bootstrap on the hub
+const bootstrapHubKubeconfigSecretName = "bootstrap-hub-kubeconfig"
+
+// EncodeImportData returns the base64 representation of an import secret value.
+// The hub may store the value either as raw yaml or as already base64 encoded data,
+// in the latter case the value is not encoded again to avoid a double encoding.
+func EncodeImportData(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return ""
+	}
+	// The decoding ignores the line breaks, the value is encoded again to get it on a single line
+	if decoded, err := base64.StdEncoding.DecodeString(string(trimmed)); err == nil && len(decoded) > 0 {
+		return base64.StdEncoding.EncodeToString(decoded)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// GetImportHubCAHash returns the sha256 of the hub CA embedded in the bootstrap hub kubeconfig
+// of an import.yaml, or an empty string if the CA can not be found.
+func GetImportHubCAHash(importYaml []byte) string {
+	if decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(importYaml))); err == nil {
+		importYaml = decoded
+	}
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(importYaml), 4096)
+	for {
+		secret := &corev1.Secret{}
+		if err := decoder.Decode(secret); err != nil {
+			return ""
+		}
+		if secret.Kind != "Secret" || secret.Name != bootstrapHubKubeconfigSecretName {
+			continue
+		}
+		kubeconfigData, ok := secret.Data["kubeconfig"]
+		if !ok {
+			kubeconfigData = []byte(secret.StringData["kubeconfig"])
+		}
+		kubeconfig, err := clientcmd.Load(kubeconfigData)
+		if err != nil {
+			return ""
+		}
+		clusterNames := make([]string, 0, len(kubeconfig.Clusters))
+		for clusterName := range kubeconfig.Clusters {
+			clusterNames = append(clusterNames, clusterName)
+		}
+		sort.Strings(clusterNames)
+		hash := sha256.New()
+		for _, clusterName := range clusterNames {
+			hash.Write(kubeconfig.Clusters[clusterName].CertificateAuthorityData)
+		}
+		if len(clusterNames) == 0 {
+			return ""
+		}
+		return hex.EncodeToString(hash.Sum(nil))
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEncodeImportDataRawYaml(t *testing.T) {
+	data := []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: open-cluster-management-agent\n")
+	expected := base64.StdEncoding.EncodeToString(data)
+	encoded := EncodeImportData(data)
+	if encoded != expected {
+		t.Fatalf(`Encoded data not as expected. Expected %s, actual %s`, expected, encoded)
+	}
+}
+
+func TestEncodeImportDataAlreadyEncoded(t *testing.T) {
+	expected := base64.StdEncoding.EncodeToString([]byte("apiVersion: v1\nkind: Namespace\n"))
+	encoded := EncodeImportData([]byte(expected + "\n"))
+	if encoded != expected {
+		t.Fatalf(`Encoded data not as expected. Expected %s, actual %s`, expected, encoded)
+	}
+}
+
+func TestEncodeImportDataEmpty(t *testing.T) {
+	if encoded := EncodeImportData(nil); encoded != "" {
+		t.Fatalf(`Encoded data not as expected. Expected empty, actual %s`, encoded)
+	}
+}
+
+func getTestImportYaml(ca string) []byte {
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://hub.example.com:6443
+    certificate-authority-data: ` + base64.StdEncoding.EncodeToString([]byte(ca)) + `
+contexts:
+- name: default
+  context:
+    cluster: hub
+current-context: default
+`
+	return []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: open-cluster-management-agent
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bootstrap-hub-kubeconfig
+  namespace: open-cluster-management-agent
+type: Opaque
+data:
+  kubeconfig: ` + base64.StdEncoding.EncodeToString([]byte(kubeconfig)) + `
+`)
+}
+
+func TestGetImportHubCAHash(t *testing.T) {
+	hash1 := GetImportHubCAHash(getTestImportYaml("ca1"))
+	if hash1 == "" {
+		t.Fatalf("Expected a hub CA hash")
+	}
+	encoded := []byte(base64.StdEncoding.EncodeToString(getTestImportYaml("ca1")))
+	if hash := GetImportHubCAHash(encoded); hash != hash1 {
+		t.Fatalf(`Hub CA hash not as expected. Expected %s, actual %s`, hash1, hash)
+	}
+	if hash := GetImportHubCAHash(getTestImportYaml("ca2")); hash == hash1 {
+		t.Fatalf("Expected a different hub CA hash for a different CA")
+	}
+	if hash := GetImportHubCAHash([]byte("my-import.yaml")); hash != "" {
+		t.Fatalf(`Expected an empty hub CA hash, actual %s`, hash)
+	}
+}
+
+func TestBuildImportCommand(t *testing.T) {
+	tmpl, err := ParseImportCommandTemplate(DefaultImportCommandTemplate)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	command, err := BuildImportCommand(tmpl, DefaultImportCommandCLI, []byte("my-crds.yaml"), []byte("my-import.yaml"), DefaultImportCommandSleep)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := `echo 'bXktY3Jkcy55YW1s' | base64 --decode | kubectl apply -f - && sleep 2 && echo 'bXktaW1wb3J0LnlhbWw=' | base64 --decode | kubectl apply -f -`
+	if command != expected {
+		t.Fatalf(`Import command not as expected. Expected %s, actual %s`, expected, command)
+	}
+
+	command, err = BuildImportCommand(tmpl, DefaultImportCommandCLI, []byte("my-crds.yaml"), []byte("my-import.yaml"), 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !strings.Contains(command, " sleep 0.5 ") {
+		t.Fatalf(`Expected a 0.5 seconds sleep, actual %s`, command)
+	}
+
+	tmpl, err = ParseImportCommandTemplate(`{{ .CLI }} apply -f <(echo {{ .Import }} | base64 -d)`)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	command, err = BuildImportCommand(tmpl, "oc", []byte("my-crds.yaml"), []byte("my-import.yaml"), DefaultImportCommandSleep)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if command != `oc apply -f <(echo bXktaW1wb3J0LnlhbWw= | base64 -d)` {
+		t.Fatalf(`Import command not as expected, actual %s`, command)
+	}
+
+	if _, err := BuildImportCommand(tmpl, "oc", nil, []byte("import"), DefaultImportCommandSleep); err == nil {
+		t.Fatalf("Expected an error for missing crds")
+	}
+	if _, err := BuildImportCommand(tmpl, "oc", []byte("my-crds.yaml"), []byte("my-import.yaml"), -time.Second); err == nil {
+		t.Fatalf("Expected an error for a negative sleep")
+	}
+	if _, err := ParseImportCommandTemplate(`{{ .CLI `); err == nil {
+		t.Fatalf("Expected an error for an invalid template")
+	}
+}
+
+func TestBuildImportCommandTrickyPayloads(t *testing.T) {
+	tmpl, err := ParseImportCommandTemplate(`{{ quote .CRDs }} {{ quote .Import }}`)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	payloads := [][]byte{
+		[]byte("name: \"quoted\"\nvalue: 'single'\n"),
+		[]byte("command: $(rm -rf /) `id` ; echo $HOME && exit 1 | cat\n"),
+		[]byte("multi\nline\r\n\ttabbed\n"),
+		// already base64 encoded with line breaks, as wrapped by some tools
+		[]byte(base64.StdEncoding.EncodeToString([]byte("apiVersion: v1\nkind: Namespace\n"))[:16] + "\n" +
+			base64.StdEncoding.EncodeToString([]byte("apiVersion: v1\nkind: Namespace\n"))[16:] + "\n"),
+		{0x00, 0xff, '\'', '"', '\\'},
+	}
+	for _, payload := range payloads {
+		command, err := BuildImportCommand(tmpl, DefaultImportCommandCLI, payload, payload, DefaultImportCommandSleep)
+		if err != nil {
+			t.Fatalf("Unexpected error %s for payload %q", err, payload)
+		}
+		args := strings.Split(command, " ")
+		if len(args) != 2 {
+			t.Fatalf(`Expected 2 arguments for payload %q, actual %s`, payload, command)
+		}
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, "'") || !strings.HasSuffix(arg, "'") {
+				t.Fatalf(`Expected a single quoted argument for payload %q, actual %s`, payload, arg)
+			}
+			encoded := strings.Trim(arg, "'")
+			if strings.ContainsAny(encoded, "'\"$`\\ \t\r\n;&|") {
+				t.Fatalf(`Expected only base64 characters for payload %q, actual %s`, payload, encoded)
+			}
+			if _, err := base64.StdEncoding.DecodeString(encoded); err != nil {
+				t.Fatalf(`Expected a base64 argument for payload %q, actual %s`, payload, encoded)
+			}
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":           `''`,
+		"abc":        `'abc'`,
+		"it's":       `'it'\''s'`,
+		"$(id) `id`": "'$(id) `id`'",
+		"a\nb":       "'a\nb'",
+	}
+	for s, expected := range tests {
+		if quoted := ShellQuote(s); quoted != expected {
+			t.Fatalf(`Quoted value not as expected. Expected %s, actual %s`, expected, quoted)
+		}
+	}
+}
+
+func TestGetImportSecretData(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: "cluster1-import"},
+		Data: map[string][]byte{
+			"crdsv1.yaml":      []byte("my-crdsv1.yaml"),
+			"crdsv1beta1.yaml": []byte("my-crdsv1beta1.yaml"),
+			"import.yaml":      []byte("my-import.yaml"),
+		},
+	}
+	crds, importData, err := GetImportSecretData(secret)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if string(crds) != "my-crdsv1.yaml" || string(importData) != "my-import.yaml" {
+		t.Fatalf("Import data not as expected, actual %s and %s", crds, importData)
+	}
+
+	// Older MCE versions name the crds key crds.yaml
+	secret.Data = map[string][]byte{
+		"crds.yaml":   []byte("my-crds.yaml"),
+		"import.yaml": []byte("my-import.yaml"),
+	}
+	if crds, _, err = GetImportSecretData(secret); err != nil || string(crds) != "my-crds.yaml" {
+		t.Fatalf("Expected the crds.yaml crds, actual %s, %v", crds, err)
+	}
+
+	secret.Data = map[string][]byte{
+		"crdsv1beta1.yaml": []byte("my-crdsv1beta1.yaml"),
+		"import.yaml":      []byte("my-import.yaml"),
+	}
+	_, _, err = GetImportSecretData(secret)
+	if err == nil {
+		t.Fatalf("Expected an error for missing crds")
+	}
+	if expected := "import secret cluster1/cluster1-import is missing crdsv1.yaml or crds.yaml, found keys [crdsv1beta1.yaml, import.yaml]"; err.Error() != expected {
+		t.Fatalf("Error not as expected. Expected %s, actual %s", expected, err)
+	}
+
+	secret.Data = map[string][]byte{
+		"crdsv1.yaml": []byte("my-crdsv1.yaml"),
+		"import.yaml": []byte(" "),
+	}
+	if _, _, err = GetImportSecretData(secret); err == nil || !strings.Contains(err.Error(), "missing import.yaml") {
+		t.Fatalf("Expected an error for empty import data, actual %v", err)
+	}
+}
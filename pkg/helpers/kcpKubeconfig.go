@@ -5,9 +5,12 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -57,3 +60,63 @@ func RestConfigForAPIExport(ctx context.Context, cfg *rest.Config, apiExportName
 
 	return cfg, nil
 }
+
+// ValidateKcpServerURL checks that the kcp server URL given to the kcp-syncer is a well-formed https URL
+// with a host and without a workspace path, as the syncer adds the workspace path itself.
+func ValidateKcpServerURL(kcpServer string) error {
+	kcpURL, err := url.Parse(kcpServer)
+	if err != nil {
+		return fmt.Errorf("kcp server URL %q is malformed: %w", kcpServer, err)
+	}
+	if kcpURL.Scheme != "https" {
+		return fmt.Errorf("kcp server URL %q is not an https URL", kcpServer)
+	}
+	if len(kcpURL.Hostname()) == 0 {
+		return fmt.Errorf("kcp server URL %q has no host", kcpServer)
+	}
+	if _, workspacePath := splitKcpWorkspacePath(kcpURL.Path); len(workspacePath) != 0 {
+		return fmt.Errorf("kcp server URL %q has a workspace path", kcpServer)
+	}
+	return nil
+}
+
+// KcpServerBaseURL returns the URL of the kcp server of the config without the clusters/<workspace> path of a
+// workspace-rooted URL, the kcp-syncer adds the path of its workspace. The services/... path of a virtual workspace
+// URL, as the APIExport one of the ComputeConfig, is removed too. The path before the workspace one, for example
+// the prefix of a proxy, is kept.
+func KcpServerBaseURL(config *rest.Config) (string, error) {
+	if config == nil || len(config.Host) == 0 {
+		return "", fmt.Errorf("the kcp server host is not set")
+	}
+	kcpURL, _, err := rest.DefaultServerURL(config.Host, "", schema.GroupVersion{}, rest.IsConfigTransportTLS(*config))
+	if err != nil {
+		return "", fmt.Errorf("kcp server URL %q is malformed: %w", config.Host, err)
+	}
+	if kcpURL.Scheme != "https" && kcpURL.Scheme != "http" {
+		return "", fmt.Errorf("kcp server URL %q has an unsupported scheme %q", config.Host, kcpURL.Scheme)
+	}
+	if len(kcpURL.Hostname()) == 0 {
+		return "", fmt.Errorf("kcp server URL %q has no host", config.Host)
+	}
+	basePath, _ := splitKcpWorkspacePath(kcpURL.Path)
+	baseURL := url.URL{
+		Scheme: kcpURL.Scheme,
+		User:   kcpURL.User,
+		Host:   kcpURL.Host,
+		Path:   strings.TrimSuffix(basePath, "/"),
+	}
+	return baseURL.String(), nil
+}
+
+// splitKcpWorkspacePath splits the path of a kcp URL in the path before its workspace path and the workspace path.
+// The workspace path is the clusters/<workspace> path of a workspace, or the services/<virtual workspace> path of a
+// virtual workspace, as the APIExport one the compute clients use. It is empty if the path has no workspace path.
+func splitKcpWorkspacePath(path string) (basePath, workspacePath string) {
+	segments := strings.Split(path, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		if (segments[i] == "clusters" || segments[i] == "services") && len(segments[i+1]) != 0 {
+			return strings.Join(segments[:i], "/"), strings.Join(segments[i:], "/")
+		}
+	}
+	return path, ""
+}
@@ -0,0 +1,58 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestValidateKcpServerURL(t *testing.T) {
+	for _, kcpServer := range []string{"https://kcp.example.com", "https://10.0.0.1:6443", "https://proxy.example.com/kcp"} {
+		if err := ValidateKcpServerURL(kcpServer); err != nil {
+			t.Fatalf("Unexpected error for %s: %s", kcpServer, err)
+		}
+	}
+	for _, kcpServer := range []string{"", "http://kcp.example.com", "https://", "https://:6443", "https://kcp.example.com/clusters/root", "https://proxy.example.com/kcp/clusters/root:org", "https://kcp.example.com/services/apiexport/root:org/compute-apis", "://kcp"} {
+		if err := ValidateKcpServerURL(kcpServer); err == nil {
+			t.Fatalf("Expected an error for %q", kcpServer)
+		}
+	}
+}
+
+func TestKcpServerBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *rest.Config
+		want    string
+		wantErr bool
+	}{
+		{name: "no path", config: &rest.Config{Host: "https://kcp.example.com:6443"}, want: "https://kcp.example.com:6443"},
+		{name: "root path", config: &rest.Config{Host: "https://kcp.example.com/"}, want: "https://kcp.example.com"},
+		{name: "workspace path", config: &rest.Config{Host: "https://kcp.example.com:6443/clusters/root:org:ws"}, want: "https://kcp.example.com:6443"},
+		{name: "workspace path with trailing slash", config: &rest.Config{Host: "https://kcp.example.com/clusters/root/"}, want: "https://kcp.example.com"},
+		{name: "proxy prefix", config: &rest.Config{Host: "https://proxy.example.com/kcp"}, want: "https://proxy.example.com/kcp"},
+		{name: "proxy prefix and workspace path", config: &rest.Config{Host: "https://proxy.example.com/kcp/clusters/root:org"}, want: "https://proxy.example.com/kcp"},
+		{name: "apiexport virtual workspace", config: &rest.Config{Host: "https://kcp.example.com:6443/services/apiexport/root:org/compute-apis"}, want: "https://kcp.example.com:6443"},
+		{name: "apiexport virtual workspace with cluster path", config: &rest.Config{Host: "https://kcp.example.com:6443/services/apiexport/root:org/compute-apis/clusters/*"}, want: "https://kcp.example.com:6443"},
+		{name: "proxy prefix and apiexport virtual workspace", config: &rest.Config{Host: "https://proxy.example.com/kcp/services/apiexport/root:org/compute-apis"}, want: "https://proxy.example.com/kcp"},
+		{name: "no scheme", config: &rest.Config{Host: "kcp.example.com:6443"}, want: "http://kcp.example.com:6443"},
+		{name: "no scheme with TLS", config: &rest.Config{Host: "kcp.example.com:6443", TLSClientConfig: rest.TLSClientConfig{Insecure: true}}, want: "https://kcp.example.com:6443"},
+		{name: "nil config", wantErr: true},
+		{name: "no host", config: &rest.Config{}, wantErr: true},
+		{name: "unsupported scheme", config: &rest.Config{Host: "ftp://kcp.example.com"}, wantErr: true},
+		{name: "empty host", config: &rest.Config{Host: "https://"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := KcpServerBaseURL(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("KcpServerBaseURL error not as expected. Expected error %t, actual %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("KcpServerBaseURL not as expected. Expected %s, actual %s", tt.want, got)
+			}
+		})
+	}
+}
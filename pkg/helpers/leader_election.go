@@ -0,0 +1,46 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"fmt"
+	"time"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+)
+
+// The default leader election durations of the compute-operator, same as the controller-runtime ones.
+const (
+	DefaultLeaderElectionLeaseDuration = 15 * time.Second
+	DefaultLeaderElectionRenewDeadline = 10 * time.Second
+)
+
+// GetLeaderElectionDurations returns the lease duration and renew deadline of the leader election configuration,
+// the defaults for the ones not set.
+func GetLeaderElectionDurations(config *singaporev1alpha1.LeaderElectionConfig) (leaseDuration, renewDeadline time.Duration) {
+	leaseDuration = DefaultLeaderElectionLeaseDuration
+	renewDeadline = DefaultLeaderElectionRenewDeadline
+	if config == nil {
+		return
+	}
+	if config.LeaseDuration != nil {
+		leaseDuration = config.LeaseDuration.Duration
+	}
+	if config.RenewDeadline != nil {
+		renewDeadline = config.RenewDeadline.Duration
+	}
+	return
+}
+
+// ValidateLeaderElectionDurations returns an error if the renew deadline is not positive or not shorter than
+// the lease duration, the leader would lose the lease before it gives up renewing it.
+func ValidateLeaderElectionDurations(leaseDuration, renewDeadline time.Duration) error {
+	if renewDeadline <= 0 {
+		return fmt.Errorf("the leader election renew deadline %s must be positive", renewDeadline)
+	}
+	if renewDeadline >= leaseDuration {
+		return fmt.Errorf("the leader election renew deadline %s must be shorter than the lease duration %s",
+			renewDeadline, leaseDuration)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetLeaderElectionDurations(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *singaporev1alpha1.LeaderElectionConfig
+		leaseDuration time.Duration
+		renewDeadline time.Duration
+	}{
+		{
+			name:          "not set",
+			leaseDuration: DefaultLeaderElectionLeaseDuration,
+			renewDeadline: DefaultLeaderElectionRenewDeadline,
+		},
+		{
+			name:          "lease duration set",
+			config:        &singaporev1alpha1.LeaderElectionConfig{LeaseDuration: &metav1.Duration{Duration: time.Minute}},
+			leaseDuration: time.Minute,
+			renewDeadline: DefaultLeaderElectionRenewDeadline,
+		},
+		{
+			name: "both set",
+			config: &singaporev1alpha1.LeaderElectionConfig{
+				LeaseDuration: &metav1.Duration{Duration: time.Minute},
+				RenewDeadline: &metav1.Duration{Duration: 40 * time.Second},
+			},
+			leaseDuration: time.Minute,
+			renewDeadline: 40 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaseDuration, renewDeadline := GetLeaderElectionDurations(tt.config)
+			if leaseDuration != tt.leaseDuration || renewDeadline != tt.renewDeadline {
+				t.Errorf("expected %s/%s, got %s/%s", tt.leaseDuration, tt.renewDeadline, leaseDuration, renewDeadline)
+			}
+		})
+	}
+}
+
+func TestValidateLeaderElectionDurations(t *testing.T) {
+	tests := []struct {
+		name          string
+		leaseDuration time.Duration
+		renewDeadline time.Duration
+		wantErr       bool
+	}{
+		{name: "defaults", leaseDuration: DefaultLeaderElectionLeaseDuration, renewDeadline: DefaultLeaderElectionRenewDeadline},
+		{name: "renew deadline equal to lease duration", leaseDuration: time.Minute, renewDeadline: time.Minute, wantErr: true},
+		{name: "renew deadline longer than lease duration", leaseDuration: 10 * time.Second, renewDeadline: time.Minute, wantErr: true},
+		{name: "renew deadline not positive", leaseDuration: time.Minute, renewDeadline: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateLeaderElectionDurations(tt.leaseDuration, tt.renewDeadline); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// SelectManagedCluster deterministically selects the ManagedCluster of a RegisteredCluster among the ManagedClusters
+// matching its labels, duplicates can be created when the ManagedCluster creation races on a slow hub.
+// A joined ManagedCluster is preferred, the user cluster may have been imported with a duplicate. Then the oldest
+// ManagedCluster is selected, the name breaks a tie. It returns the selected ManagedCluster, the duplicates and whether
+// a ManagedCluster was found.
+func SelectManagedCluster(managedClusters []clusterapiv1.ManagedCluster) (clusterapiv1.ManagedCluster, []clusterapiv1.ManagedCluster, bool) {
+	if len(managedClusters) == 0 {
+		return clusterapiv1.ManagedCluster{}, nil, false
+	}
+	sorted := append([]clusterapiv1.ManagedCluster{}, managedClusters...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if joinedI, joinedJ := isManagedClusterJoined(sorted[i]), isManagedClusterJoined(sorted[j]); joinedI != joinedJ {
+			return joinedI
+		}
+		if !sorted[i].CreationTimestamp.Equal(&sorted[j].CreationTimestamp) {
+			return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp)
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted[0], sorted[1:], true
+}
+
+func isManagedClusterJoined(managedCluster clusterapiv1.ManagedCluster) bool {
+	status, ok := GetConditionStatus(managedCluster.Status.Conditions, clusterapiv1.ManagedClusterConditionJoined)
+	return ok && status == metav1.ConditionTrue
+}
+
+// MergeManagedClusterAnnotations returns the annotations of a ManagedCluster, the given annotations merged with the
+// reserved annotations of the operator. The reserved annotations can't be overwritten, the keys of the given
+// annotations clashing with them are returned sorted.
+func MergeManagedClusterAnnotations(reserved, annotations map[string]string) (map[string]string, []string) {
+	merged := make(map[string]string, len(reserved)+len(annotations))
+	var ignored []string
+	for key, value := range annotations {
+		if _, ok := reserved[key]; ok {
+			ignored = append(ignored, key)
+			continue
+		}
+		merged[key] = value
+	}
+	for key, value := range reserved {
+		merged[key] = value
+	}
+	sort.Strings(ignored)
+	return merged, ignored
+}
+
+// PropagatedLabelsAnnotation is the ManagedCluster annotation listing the RegisteredCluster labels propagated to it,
+// so a label removed from the RegisteredCluster is removed from the ManagedCluster.
+const PropagatedLabelsAnnotation string = "registeredcluster.singapore.open-cluster-management.io/propagated-labels"
+
+// reservedLabelPrefixes are the prefixes of the labels which are not propagated from the RegisteredCluster to the
+// ManagedCluster, they are set by the operator, open-cluster-management or kcp.
+var reservedLabelPrefixes = []string{
+	"registeredcluster.singapore.open-cluster-management.io/",
+	"cluster.open-cluster-management.io/",
+	"feature.open-cluster-management.io/",
+	"kcp.dev/",
+}
+
+// reservedLabels are the labels which are not propagated from the RegisteredCluster to the ManagedCluster, they are
+// set by the open-cluster-management cluster claims and placements rely on them.
+var reservedLabels = []string{
+	"name",
+	"vendor",
+	"cloud",
+	"clusterID",
+}
+
+// GetPropagatedLabels returns the RegisteredCluster labels to propagate to its ManagedCluster. The reserved labels
+// and the labels with a reserved prefix are excluded.
+func GetPropagatedLabels(labels, reserved map[string]string) map[string]string {
+	propagated := map[string]string{}
+	for key, value := range labels {
+		if _, ok := reserved[key]; ok || hasReservedLabelPrefix(key) {
+			continue
+		}
+		propagated[key] = value
+	}
+	return propagated
+}
+
+func hasReservedLabelPrefix(key string) bool {
+	for _, label := range reservedLabels {
+		if key == label {
+			return true
+		}
+	}
+	for _, prefix := range reservedLabelPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncPropagatedLabels sets the propagated labels on the ManagedCluster and removes the previously propagated labels
+// which are no longer propagated. The propagated label keys are recorded in the PropagatedLabelsAnnotation.
+// It returns true if the ManagedCluster was changed.
+func SyncPropagatedLabels(managedCluster *clusterapiv1.ManagedCluster, propagated map[string]string) bool {
+	changed := false
+	labels := managedCluster.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for _, key := range strings.Split(managedCluster.GetAnnotations()[PropagatedLabelsAnnotation], ",") {
+		if _, ok := propagated[key]; ok || len(key) == 0 {
+			continue
+		}
+		if _, ok := labels[key]; ok {
+			delete(labels, key)
+			changed = true
+		}
+	}
+	keys := make([]string, 0, len(propagated))
+	for key, value := range propagated {
+		if current, ok := labels[key]; !ok || current != value {
+			labels[key] = value
+			changed = true
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	managedCluster.SetLabels(labels)
+
+	annotations := managedCluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	propagatedKeys := strings.Join(keys, ",")
+	if current, ok := annotations[PropagatedLabelsAnnotation]; ok != (len(keys) != 0) || current != propagatedKeys {
+		if len(keys) == 0 {
+			delete(annotations, PropagatedLabelsAnnotation)
+		} else {
+			annotations[PropagatedLabelsAnnotation] = propagatedKeys
+		}
+		changed = true
+	}
+	managedCluster.SetAnnotations(annotations)
+	return changed
+}
@@ -0,0 +1,171 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func getTestManagedCluster(name string, created time.Time) clusterapiv1.ManagedCluster {
+	return clusterapiv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+}
+
+func TestSelectManagedClusterNone(t *testing.T) {
+	if _, _, ok := SelectManagedCluster(nil); ok {
+		t.Fatalf("ManagedCluster found but expected to be not found.")
+	}
+}
+
+func TestSelectManagedClusterOne(t *testing.T) {
+	now := time.Now()
+	selected, duplicates, ok := SelectManagedCluster([]clusterapiv1.ManagedCluster{
+		getTestManagedCluster("registered-cluster-abcde", now),
+	})
+	if !ok || selected.Name != "registered-cluster-abcde" {
+		t.Fatalf(`ManagedCluster not as expected. Expected registered-cluster-abcde, actual %s (found %t)`, selected.Name, ok)
+	}
+	if len(duplicates) != 0 {
+		t.Fatalf(`Number of duplicates not as expected. Expected 0, actual %d`, len(duplicates))
+	}
+}
+
+func TestSelectManagedClusterMultiple(t *testing.T) {
+	now := time.Now()
+	selected, duplicates, ok := SelectManagedCluster([]clusterapiv1.ManagedCluster{
+		getTestManagedCluster("registered-cluster-aaaaa", now),
+		getTestManagedCluster("registered-cluster-ccccc", now.Add(-time.Minute)),
+		getTestManagedCluster("registered-cluster-bbbbb", now.Add(-time.Minute)),
+	})
+	if !ok || selected.Name != "registered-cluster-bbbbb" {
+		t.Fatalf(`ManagedCluster not as expected. Expected registered-cluster-bbbbb, actual %s (found %t)`, selected.Name, ok)
+	}
+	if len(duplicates) != 2 || duplicates[0].Name != "registered-cluster-ccccc" || duplicates[1].Name != "registered-cluster-aaaaa" {
+		t.Fatalf(`Duplicates not as expected, actual %v`, duplicates)
+	}
+}
+
+func TestSelectManagedClusterJoined(t *testing.T) {
+	now := time.Now()
+	joined := getTestManagedCluster("registered-cluster-ccccc", now)
+	joined.Status.Conditions = []metav1.Condition{
+		{Type: clusterapiv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue},
+	}
+	selected, duplicates, ok := SelectManagedCluster([]clusterapiv1.ManagedCluster{
+		getTestManagedCluster("registered-cluster-aaaaa", now.Add(-time.Minute)),
+		joined,
+		getTestManagedCluster("registered-cluster-bbbbb", now.Add(-2*time.Minute)),
+	})
+	if !ok || selected.Name != "registered-cluster-ccccc" {
+		t.Fatalf(`ManagedCluster not as expected. Expected registered-cluster-ccccc, actual %s (found %t)`, selected.Name, ok)
+	}
+	if len(duplicates) != 2 || duplicates[0].Name != "registered-cluster-bbbbb" || duplicates[1].Name != "registered-cluster-aaaaa" {
+		t.Fatalf(`Duplicates not as expected, actual %v`, duplicates)
+	}
+}
+
+func TestMergeManagedClusterAnnotations(t *testing.T) {
+	reserved := map[string]string{
+		"open-cluster-management/service-name":                               "compute",
+		"registeredcluster.singapore.open-cluster-management.io/clustername": "root:compute",
+	}
+	annotations, ignored := MergeManagedClusterAnnotations(reserved, map[string]string{
+		"open-cluster-management/service-name":                               "other",
+		"registeredcluster.singapore.open-cluster-management.io/clustername": "root:other",
+		"cost-center": "1234",
+	})
+	expected := map[string]string{
+		"open-cluster-management/service-name":                               "compute",
+		"registeredcluster.singapore.open-cluster-management.io/clustername": "root:compute",
+		"cost-center": "1234",
+	}
+	if !reflect.DeepEqual(annotations, expected) {
+		t.Fatalf(`Annotations not as expected. Expected %v, actual %v`, expected, annotations)
+	}
+	if !reflect.DeepEqual(ignored, []string{"open-cluster-management/service-name", "registeredcluster.singapore.open-cluster-management.io/clustername"}) {
+		t.Fatalf(`Ignored annotations not as expected. Expected the reserved annotations, actual %v`, ignored)
+	}
+
+	annotations, ignored = MergeManagedClusterAnnotations(reserved, nil)
+	if !reflect.DeepEqual(annotations, reserved) || len(ignored) != 0 {
+		t.Fatalf(`Expected only the reserved annotations, actual %v, ignored %v`, annotations, ignored)
+	}
+}
+
+func TestGetPropagatedLabels(t *testing.T) {
+	reserved := map[string]string{
+		"registeredcluster.singapore.open-cluster-management.io/name": "cluster1",
+	}
+	propagated := GetPropagatedLabels(map[string]string{
+		"registeredcluster.singapore.open-cluster-management.io/name": "other",
+		"cluster.open-cluster-management.io/clusterset":               "other",
+		"feature.open-cluster-management.io/addon-work-manager":       "available",
+		"name":      "other",
+		"vendor":    "OpenShift",
+		"cloud":     "Amazon",
+		"clusterID": "other",
+		"team":      "team1",
+		"region":    "eu",
+	}, reserved)
+	expected := map[string]string{"team": "team1", "region": "eu"}
+	if !reflect.DeepEqual(propagated, expected) {
+		t.Fatalf(`Propagated labels not as expected. Expected %v, actual %v`, expected, propagated)
+	}
+}
+
+func TestSyncPropagatedLabels(t *testing.T) {
+	managedCluster := &clusterapiv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"registeredcluster.singapore.open-cluster-management.io/name": "cluster1",
+				"hub-label": "hub",
+			},
+		},
+	}
+	if !SyncPropagatedLabels(managedCluster, map[string]string{"team": "team1", "region": "eu"}) {
+		t.Fatalf(`Expected the managedcluster to be changed`)
+	}
+	if managedCluster.Labels["team"] != "team1" || managedCluster.Labels["region"] != "eu" {
+		t.Fatalf(`Expected the labels to be propagated, actual %v`, managedCluster.Labels)
+	}
+	if managedCluster.Annotations[PropagatedLabelsAnnotation] != "region,team" {
+		t.Fatalf(`Propagated labels annotation not as expected. Expected region,team, actual %s`, managedCluster.Annotations[PropagatedLabelsAnnotation])
+	}
+	if SyncPropagatedLabels(managedCluster, map[string]string{"team": "team1", "region": "eu"}) {
+		t.Fatalf(`Expected the managedcluster not to be changed`)
+	}
+
+	if !SyncPropagatedLabels(managedCluster, map[string]string{"team": "team2"}) {
+		t.Fatalf(`Expected the managedcluster to be changed`)
+	}
+	expected := map[string]string{
+		"registeredcluster.singapore.open-cluster-management.io/name": "cluster1",
+		"hub-label": "hub",
+		"team":      "team2",
+	}
+	if !reflect.DeepEqual(managedCluster.Labels, expected) {
+		t.Fatalf(`Labels not as expected. Expected %v, actual %v`, expected, managedCluster.Labels)
+	}
+
+	if !SyncPropagatedLabels(managedCluster, map[string]string{}) {
+		t.Fatalf(`Expected the managedcluster to be changed`)
+	}
+	if _, ok := managedCluster.Labels["team"]; ok {
+		t.Fatalf(`Expected the team label to be removed, actual %v`, managedCluster.Labels)
+	}
+	if _, ok := managedCluster.Annotations[PropagatedLabelsAnnotation]; ok {
+		t.Fatalf(`Expected the propagated labels annotation to be removed`)
+	}
+	if managedCluster.Labels["hub-label"] != "hub" {
+		t.Fatalf(`Expected the labels not propagated to be kept, actual %v`, managedCluster.Labels)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"fmt"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	manifestworkv1 "open-cluster-management.io/api/work/v1"
+)
+
+// GetFeedbackValue returns the status feedback value with the given name reported for a resource of the manifestwork
+// and whether it was found.
+func GetFeedbackValue(work *manifestworkv1.ManifestWork,
+	group, resource, namespace, name string,
+	feedbackName string) (manifestworkv1.FieldValue, bool) {
+	for _, manifest := range work.Status.ResourceStatus.Manifests {
+		meta := manifest.ResourceMeta
+		if meta.Group != group || meta.Resource != resource || meta.Namespace != namespace || meta.Name != name {
+			continue
+		}
+		for _, value := range manifest.StatusFeedbacks.Values {
+			if value.Name == feedbackName {
+				return value.Value, true
+			}
+		}
+	}
+	return manifestworkv1.FieldValue{}, false
+}
+
+// GetSyncerAvailableCondition returns the SyncerAvailable condition of a RegisteredCluster from the
+// conditions of its kcp-syncer manifestwork. The condition is false while the manifestwork is not applied,
+// is degraded or its resources are not available.
+func GetSyncerAvailableCondition(work *manifestworkv1.ManifestWork) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionSyncerAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  "SyncerAvailable",
+		Message: fmt.Sprintf("The kcp-syncer manifestwork %s is applied and available", work.Name),
+	}
+	workCondition := meta.FindStatusCondition(work.Status.Conditions, manifestworkv1.WorkDegraded)
+	if workCondition != nil && workCondition.Status == metav1.ConditionTrue {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncerDegraded"
+		condition.Message = fmt.Sprintf("The kcp-syncer manifestwork %s is degraded: %s", work.Name, workCondition.Message)
+		return condition
+	}
+	workCondition = meta.FindStatusCondition(work.Status.Conditions, manifestworkv1.WorkApplied)
+	if workCondition == nil || workCondition.Status != metav1.ConditionTrue {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncerNotApplied"
+		condition.Message = fmt.Sprintf("The kcp-syncer manifestwork %s is not applied", work.Name)
+		if workCondition != nil && len(workCondition.Message) != 0 {
+			condition.Message = fmt.Sprintf("%s: %s", condition.Message, workCondition.Message)
+		}
+		return condition
+	}
+	workCondition = meta.FindStatusCondition(work.Status.Conditions, manifestworkv1.WorkAvailable)
+	if workCondition == nil || workCondition.Status != metav1.ConditionTrue {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SyncerUnavailable"
+		condition.Message = fmt.Sprintf("The resources of the kcp-syncer manifestwork %s are not available", work.Name)
+		if workCondition != nil && len(workCondition.Message) != 0 {
+			condition.Message = fmt.Sprintf("%s: %s", condition.Message, workCondition.Message)
+		}
+	}
+	return condition
+}
@@ -0,0 +1,105 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	manifestworkv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestGetFeedbackValue(t *testing.T) {
+	unavailable := int64(1)
+	work := &manifestworkv1.ManifestWork{
+		Status: manifestworkv1.ManifestWorkStatus{
+			ResourceStatus: manifestworkv1.ManifestResourceStatus{
+				Manifests: []manifestworkv1.ManifestCondition{
+					{
+						ResourceMeta: manifestworkv1.ManifestResourceMeta{
+							Group:     "apps",
+							Resource:  "deployments",
+							Namespace: "kcp-syncer-ns",
+							Name:      "kcp-syncer",
+						},
+						StatusFeedbacks: manifestworkv1.StatusFeedbackResult{
+							Values: []manifestworkv1.FeedbackValue{
+								{
+									Name: "unavailableReplicas",
+									Value: manifestworkv1.FieldValue{
+										Type:    manifestworkv1.Integer,
+										Integer: &unavailable,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	value, ok := GetFeedbackValue(work, "apps", "deployments", "kcp-syncer-ns", "kcp-syncer", "unavailableReplicas")
+	if !ok {
+		t.Fatalf("Feedback value not found as expected.")
+	}
+	if value.Integer == nil || *value.Integer != 1 {
+		t.Fatalf("Feedback value not as expected. Expected 1, actual %v", value.Integer)
+	}
+	if _, ok := GetFeedbackValue(work, "apps", "deployments", "other-ns", "kcp-syncer", "unavailableReplicas"); ok {
+		t.Fatalf("Feedback value found but expected to be not found.")
+	}
+}
+
+func TestGetSyncerAvailableCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		status     metav1.ConditionStatus
+		reason     string
+	}{
+		{
+			name:   "no condition",
+			status: metav1.ConditionFalse,
+			reason: "SyncerNotApplied",
+		},
+		{
+			name: "applied but not available",
+			conditions: []metav1.Condition{
+				{Type: manifestworkv1.WorkApplied, Status: metav1.ConditionTrue},
+				{Type: manifestworkv1.WorkAvailable, Status: metav1.ConditionFalse},
+			},
+			status: metav1.ConditionFalse,
+			reason: "SyncerUnavailable",
+		},
+		{
+			name: "applied and available",
+			conditions: []metav1.Condition{
+				{Type: manifestworkv1.WorkApplied, Status: metav1.ConditionTrue},
+				{Type: manifestworkv1.WorkAvailable, Status: metav1.ConditionTrue},
+			},
+			status: metav1.ConditionTrue,
+			reason: "SyncerAvailable",
+		},
+		{
+			name: "degraded",
+			conditions: []metav1.Condition{
+				{Type: manifestworkv1.WorkApplied, Status: metav1.ConditionTrue},
+				{Type: manifestworkv1.WorkAvailable, Status: metav1.ConditionTrue},
+				{Type: manifestworkv1.WorkDegraded, Status: metav1.ConditionTrue},
+			},
+			status: metav1.ConditionFalse,
+			reason: "SyncerDegraded",
+		},
+	}
+	for _, test := range tests {
+		work := &manifestworkv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Name: "kcp-syncer"},
+			Status:     manifestworkv1.ManifestWorkStatus{Conditions: test.conditions},
+		}
+		condition := GetSyncerAvailableCondition(work)
+		if condition.Status != test.status || condition.Reason != test.reason {
+			t.Fatalf(`%s: condition not as expected. Expected %s/%s, actual %s/%s`,
+				test.name, test.status, test.reason, condition.Status, condition.Reason)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// PprofServer is a manager runnable serving the pprof endpoints.
+type PprofServer struct {
+	Addr string
+}
+
+// Start serves the pprof endpoints until the context is done.
+func (s *PprofServer) Start(ctx context.Context) error {
+	log := ctrl.Log.WithName("pprof")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		if err := server.Shutdown(context.Background()); err != nil {
+			log.Error(err, "unable to shutdown the pprof server")
+		}
+	}()
+	log.Info("serving pprof", "address", s.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false so the endpoints are available on all replicas.
+func (s *PprofServer) NeedLeaderElection() bool {
+	return false
+}
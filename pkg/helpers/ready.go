@@ -0,0 +1,54 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// GetReadyCondition computes the Ready condition of a RegisteredCluster from its status. The RegisteredCluster is
+// ready when its cluster was imported and joined, and its kcp-syncer manifestwork, if deployed, is applied and available.
+// The import command is removed once the cluster joined, so the join implies the import.
+func GetReadyCondition(regCluster *singaporev1alpha1.RegisteredCluster) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    singaporev1alpha1.RegisteredClusterConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Ready",
+		Message: "The cluster joined and its kcp-syncer is available",
+	}
+	conditions := regCluster.Status.Conditions
+	if status, ok := GetConditionStatus(conditions, clusterapiv1.ManagedClusterConditionJoined); !ok || status != metav1.ConditionTrue {
+		if len(regCluster.Status.ImportCommandRef.Name) == 0 {
+			condition.Reason = "ImportCommandPending"
+			condition.Message = "The import command of the cluster is not yet generated"
+			return condition
+		}
+		condition.Reason = "NotJoined"
+		condition.Message = "The cluster has not yet joined, run the import command on the cluster"
+		return condition
+	}
+	if len(regCluster.Spec.Location) == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Message = "The cluster joined"
+		return condition
+	}
+	if !IsSyncerDeployed(regCluster) {
+		condition.Status = metav1.ConditionTrue
+		condition.Message = "The cluster joined, its kcp-syncer is not deployed"
+		return condition
+	}
+	if status, ok := GetConditionStatus(conditions, singaporev1alpha1.RegisteredClusterConditionSyncerEvicted); ok && status == metav1.ConditionTrue {
+		condition.Reason = "SyncerEvicted"
+		condition.Message = "The kcp-syncer is evicted from the cluster"
+		return condition
+	}
+	if status, ok := GetConditionStatus(conditions, singaporev1alpha1.RegisteredClusterConditionSyncerAvailable); !ok || status != metav1.ConditionTrue {
+		condition.Reason = "SyncerNotAvailable"
+		condition.Message = "The kcp-syncer manifestwork is not yet applied or available"
+		return condition
+	}
+	condition.Status = metav1.ConditionTrue
+	return condition
+}
@@ -0,0 +1,52 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func TestGetReadyCondition(t *testing.T) {
+	joined := metav1.Condition{Type: clusterapiv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue}
+	syncerAvailable := metav1.Condition{Type: singaporev1alpha1.RegisteredClusterConditionSyncerAvailable, Status: metav1.ConditionTrue}
+	syncerUnavailable := metav1.Condition{Type: singaporev1alpha1.RegisteredClusterConditionSyncerAvailable, Status: metav1.ConditionFalse}
+	syncerEvicted := metav1.Condition{Type: singaporev1alpha1.RegisteredClusterConditionSyncerEvicted, Status: metav1.ConditionTrue}
+	notDeployed := false
+	tests := []struct {
+		name             string
+		location         []string
+		deploySyncer     *bool
+		importCommandRef corev1.SecretReference
+		conditions       []metav1.Condition
+		status           metav1.ConditionStatus
+		reason           string
+	}{
+		{name: "new", status: metav1.ConditionFalse, reason: "ImportCommandPending"},
+		{name: "not joined", importCommandRef: corev1.SecretReference{Name: "import"}, status: metav1.ConditionFalse, reason: "NotJoined"},
+		{name: "joined without location", conditions: []metav1.Condition{joined}, status: metav1.ConditionTrue, reason: "Ready"},
+		{name: "syncer not applied", location: []string{"root:ws"}, conditions: []metav1.Condition{joined}, status: metav1.ConditionFalse, reason: "SyncerNotAvailable"},
+		{name: "syncer unavailable", location: []string{"root:ws"}, conditions: []metav1.Condition{joined, syncerUnavailable}, status: metav1.ConditionFalse, reason: "SyncerNotAvailable"},
+		{name: "syncer evicted", location: []string{"root:ws"}, conditions: []metav1.Condition{joined, syncerAvailable, syncerEvicted}, status: metav1.ConditionFalse, reason: "SyncerEvicted"},
+		{name: "syncer not deployed", location: []string{"root:ws"}, deploySyncer: &notDeployed, conditions: []metav1.Condition{joined}, status: metav1.ConditionTrue, reason: "Ready"},
+		{name: "ready", location: []string{"root:ws"}, conditions: []metav1.Condition{joined, syncerAvailable}, status: metav1.ConditionTrue, reason: "Ready"},
+	}
+	for _, test := range tests {
+		regCluster := &singaporev1alpha1.RegisteredCluster{
+			Spec: singaporev1alpha1.RegisteredClusterSpec{Location: test.location, DeploySyncer: test.deploySyncer},
+			Status: singaporev1alpha1.RegisteredClusterStatus{
+				Conditions:       test.conditions,
+				ImportCommandRef: test.importCommandRef,
+			},
+		}
+		condition := GetReadyCondition(regCluster)
+		if condition.Status != test.status || condition.Reason != test.reason {
+			t.Fatalf(`%s: ready condition not as expected. Expected %s/%s, actual %s/%s`,
+				test.name, test.status, test.reason, condition.Status, condition.Reason)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// RequeueBackoff computes the requeues of a reconciler. The delay of consecutive requeues of an object grows
+// exponentially up to MaxDelay and is jittered, so the retries of many objects are spread and bounded.
+type RequeueBackoff struct {
+	// MaxDelay caps the delay before the jitter is added, no cap if zero.
+	MaxDelay time.Duration
+	// Jitter is the maximum factor of the delay randomly added, for example 0.1 adds up to 10%.
+	Jitter float64
+
+	mutex    sync.Mutex
+	attempts map[string]int
+}
+
+// Requeue returns the result to requeue the object with the given key. The delay is doubled
+// for each consecutive requeue of the object until Forget is called.
+func (b *RequeueBackoff) Requeue(key string, delay time.Duration) ctrl.Result {
+	if b == nil {
+		return ctrl.Result{Requeue: true, RequeueAfter: delay}
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.attempts == nil {
+		b.attempts = map[string]int{}
+	}
+	attempt := b.attempts[key]
+	b.attempts[key] = attempt + 1
+	for i := 0; i < attempt && (b.MaxDelay == 0 || delay < b.MaxDelay); i++ {
+		delay *= 2
+	}
+	if b.MaxDelay != 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+	return ctrl.Result{Requeue: true, RequeueAfter: b.jitter(delay)}
+}
+
+// Resync returns the result to requeue the object after the given period, only the jitter is added.
+func (b *RequeueBackoff) Resync(period time.Duration) ctrl.Result {
+	if b == nil {
+		return ctrl.Result{RequeueAfter: period}
+	}
+	return ctrl.Result{RequeueAfter: b.jitter(period)}
+}
+
+// Forget resets the backoff of the object with the given key.
+func (b *RequeueBackoff) Forget(key string) {
+	if b == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.attempts, key)
+}
+
+func (b *RequeueBackoff) jitter(delay time.Duration) time.Duration {
+	if b.Jitter <= 0 {
+		return delay
+	}
+	return wait.Jitter(delay, b.Jitter)
+}
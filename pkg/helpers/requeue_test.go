@@ -0,0 +1,32 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRequeueBackoff(t *testing.T) {
+	backoff := &RequeueBackoff{MaxDelay: 5 * time.Second}
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		result := backoff.Requeue("ws|ns/name", time.Second)
+		if result.RequeueAfter != expected {
+			t.Fatalf(`Requeue delay not as expected. Expected %s, actual %s`, expected, result.RequeueAfter)
+		}
+	}
+	backoff.Forget("ws|ns/name")
+	if result := backoff.Requeue("ws|ns/name", time.Second); result.RequeueAfter != time.Second {
+		t.Fatalf(`Requeue delay not reset. Expected %s, actual %s`, time.Second, result.RequeueAfter)
+	}
+}
+
+func TestRequeueBackoffJitter(t *testing.T) {
+	backoff := &RequeueBackoff{Jitter: 0.5}
+	for i := 0; i < 10; i++ {
+		result := backoff.Resync(time.Minute)
+		if result.RequeueAfter < time.Minute || result.RequeueAfter > 90*time.Second {
+			t.Fatalf(`Resync delay out of the jitter range, actual %s`, result.RequeueAfter)
+		}
+	}
+}
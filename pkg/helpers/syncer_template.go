@@ -0,0 +1,113 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/stolostron/applier/pkg/asset"
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultSyncerTemplate is the name of the kcp-syncer manifestwork template used if none is selected.
+const DefaultSyncerTemplate = "default"
+
+// LimitedSyncerTemplate is the name of the former template setting resource requests and limits on the kcp-syncer.
+// The default template sets them now, the name is kept as an alias of the default template.
+const LimitedSyncerTemplate = "limited"
+
+// bundledSyncerTemplatePath is the path of the bundled kcp-syncer manifestwork template
+const bundledSyncerTemplatePath = "cluster-registration/kcp_syncer_manifestwork.yaml"
+
+// IsBundledSyncerTemplate returns true if the kcp-syncer manifestwork template with the given name is the bundled one.
+func IsBundledSyncerTemplate(name string) bool {
+	return len(name) == 0 || name == DefaultSyncerTemplate || name == LimitedSyncerTemplate
+}
+
+// GetSyncerTemplate returns the reader and the path of the kcp-syncer manifestwork template with the given name.
+// The bundled template is read from the bundled reader, the other templates are the keys of the syncer templates
+// ConfigMap of the HubConfig. An error is returned if the template is found in neither.
+func GetSyncerTemplate(bundled asset.ScenarioReader, templates *corev1.ConfigMap, name string) (asset.ScenarioReader, string, error) {
+	if IsBundledSyncerTemplate(name) {
+		if _, err := bundled.Asset(bundledSyncerTemplatePath); err != nil {
+			return nil, "", fmt.Errorf("syncer template %s not found: %w", name, err)
+		}
+		return bundled, bundledSyncerTemplatePath, nil
+	}
+	if templates == nil {
+		return nil, "", fmt.Errorf("syncer template %s not found, the HubConfig has no syncer templates ConfigMap", name)
+	}
+	if _, ok := templates.Data[name]; !ok {
+		return nil, "", fmt.Errorf("syncer template %s not found in the ConfigMap %s/%s", name, templates.Namespace, templates.Name)
+	}
+	return &configMapReader{configMap: templates}, name, nil
+}
+
+// configMapReader reads the assets from the keys of a ConfigMap
+type configMapReader struct {
+	configMap *corev1.ConfigMap
+}
+
+var _ asset.ScenarioReader = &configMapReader{}
+
+func (r *configMapReader) Asset(name string) ([]byte, error) {
+	data, ok := r.configMap.Data[name]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in the ConfigMap %s/%s", name, r.configMap.Namespace, r.configMap.Name)
+	}
+	return []byte(data), nil
+}
+
+func (r *configMapReader) AssetNames(excluded []string) ([]string, error) {
+	names := []string{}
+	for name := range r.configMap.Data {
+		if !containsString(excluded, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (r *configMapReader) ExtractAssets(prefix, dir string, excluded []string) error {
+	return fmt.Errorf("extracting the assets of the ConfigMap %s/%s is not supported", r.configMap.Namespace, r.configMap.Name)
+}
+
+func (r *configMapReader) ToJSON(b []byte) ([]byte, error) {
+	return yaml.YAMLToJSON(b)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSyncerProxyEnv returns the proxy environment variables of the kcp-syncer container, from the RegisteredCluster
+// proxy configuration else from the HubConfig one. The empty values are omitted, nil is returned if none is set.
+func GetSyncerProxyEnv(regClusterProxy, hubConfigProxy *singaporev1alpha1.SyncerProxyConfig) []corev1.EnvVar {
+	proxy := regClusterProxy
+	if proxy == nil {
+		proxy = hubConfigProxy
+	}
+	if proxy == nil {
+		return nil
+	}
+	var env []corev1.EnvVar
+	for _, envVar := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: proxy.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: proxy.HTTPSProxy},
+		{Name: "NO_PROXY", Value: proxy.NoProxy},
+	} {
+		if len(envVar.Value) != 0 {
+			env = append(env, envVar)
+		}
+	}
+	return env
+}
@@ -0,0 +1,81 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"reflect"
+	"testing"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSyncerTemplate(t *testing.T) {
+	bundled := resources.GetScenarioResourcesReader()
+	for _, name := range []string{"", "default", "limited"} {
+		reader, path, err := GetSyncerTemplate(bundled, nil, name)
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		if reader != bundled || path != "cluster-registration/kcp_syncer_manifestwork.yaml" {
+			t.Fatalf(`Template path not as expected. Expected cluster-registration/kcp_syncer_manifestwork.yaml, actual %s`, path)
+		}
+	}
+	if _, _, err := GetSyncerTemplate(bundled, nil, "custom"); err == nil {
+		t.Fatalf("Expected an error for a template without a syncer templates ConfigMap")
+	}
+
+	templates := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "syncer-templates", Namespace: "hub"},
+		Data:       map[string]string{"custom": "kind: ManifestWork"},
+	}
+	reader, path, err := GetSyncerTemplate(bundled, templates, "custom")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if path != "custom" {
+		t.Fatalf(`Template path not as expected. Expected custom, actual %s`, path)
+	}
+	data, err := reader.Asset(path)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if string(data) != "kind: ManifestWork" {
+		t.Fatalf(`Template not as expected. Expected kind: ManifestWork, actual %s`, string(data))
+	}
+	if _, _, err := GetSyncerTemplate(bundled, templates, "unknown"); err == nil {
+		t.Fatalf("Expected an error for an unknown template")
+	}
+}
+
+func TestGetSyncerProxyEnv(t *testing.T) {
+	if env := GetSyncerProxyEnv(nil, nil); env != nil {
+		t.Fatalf(`Expected no proxy env, actual %v`, env)
+	}
+	hubConfigProxy := &singaporev1alpha1.SyncerProxyConfig{
+		HTTPProxy:  "http://hub-proxy:3128",
+		HTTPSProxy: "http://hub-proxy:3128",
+		NoProxy:    ".cluster.local",
+	}
+	env := GetSyncerProxyEnv(nil, hubConfigProxy)
+	expected := []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: "http://hub-proxy:3128"},
+		{Name: "HTTPS_PROXY", Value: "http://hub-proxy:3128"},
+		{Name: "NO_PROXY", Value: ".cluster.local"},
+	}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf(`Proxy env not as expected. Expected %v, actual %v`, expected, env)
+	}
+	env = GetSyncerProxyEnv(&singaporev1alpha1.SyncerProxyConfig{HTTPSProxy: "http://proxy:3128"}, hubConfigProxy)
+	expected = []corev1.EnvVar{
+		{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+	}
+	if !reflect.DeepEqual(env, expected) {
+		t.Fatalf(`Proxy env not as expected. Expected %v, actual %v`, expected, env)
+	}
+	if env := GetSyncerProxyEnv(&singaporev1alpha1.SyncerProxyConfig{}, nil); env != nil {
+		t.Fatalf(`Expected no proxy env for an empty proxy configuration, actual %v`, env)
+	}
+}
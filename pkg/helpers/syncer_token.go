@@ -0,0 +1,78 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultSyncerTokenExpiration is the expiration of the bound tokens minted for the kcp-syncer.
+	DefaultSyncerTokenExpiration = 24 * time.Hour
+	// MinSyncerTokenExpiration is the minimum expiration of a bound token accepted by the TokenRequest API.
+	MinSyncerTokenExpiration = 10 * time.Minute
+	// SyncerTokenExpirationAnnotation is the annotation of the bound token secret holding the RFC3339
+	// expiration time of the token.
+	SyncerTokenExpirationAnnotation = "singapore.open-cluster-management.io/token-expiration"
+)
+
+// GetSyncerTokenHash returns the SHA-256 hash of a kcp-syncer token, it identifies the token in the
+// RegisteredCluster status without exposing it.
+func GetSyncerTokenHash(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// FindSyncerTokenStatus returns the token status of the given location, nil if the location has none.
+func FindSyncerTokenStatus(tokens []singaporev1alpha1.SyncerTokenStatus, locationPath string) *singaporev1alpha1.SyncerTokenStatus {
+	for i := range tokens {
+		if tokens[i].LocationPath == locationPath {
+			return &tokens[i]
+		}
+	}
+	return nil
+}
+
+// SetSyncerTokenStatus returns a copy of the token statuses with the one of the location of the given status
+// replaced, or added if the location has none.
+func SetSyncerTokenStatus(tokens []singaporev1alpha1.SyncerTokenStatus,
+	tokenStatus singaporev1alpha1.SyncerTokenStatus) []singaporev1alpha1.SyncerTokenStatus {
+	newTokens := make([]singaporev1alpha1.SyncerTokenStatus, 0, len(tokens)+1)
+	found := false
+	for _, token := range tokens {
+		if token.LocationPath == tokenStatus.LocationPath {
+			token = tokenStatus
+			found = true
+		}
+		newTokens = append(newTokens, token)
+	}
+	if !found {
+		newTokens = append(newTokens, tokenStatus)
+	}
+	return newTokens
+}
+
+// GetSyncerTokenExpiration returns the expiration time of the bound token of the secret, nil if the token
+// doesn't expire or the annotation can't be parsed.
+func GetSyncerTokenExpiration(secret *corev1.Secret) *metav1.Time {
+	expiration, err := time.Parse(time.RFC3339, secret.GetAnnotations()[SyncerTokenExpirationAnnotation])
+	if err != nil {
+		return nil
+	}
+	expirationTime := metav1.NewTime(expiration)
+	return &expirationTime
+}
+
+// IsSyncerTokenExpiring returns true if a bound token minted with the given lifetime must be renewed, the token
+// is renewed when 80% of its lifetime is elapsed. A token without expiration time is always renewed.
+func IsSyncerTokenExpiring(expiration *metav1.Time, lifetime time.Duration, now time.Time) bool {
+	if expiration == nil {
+		return true
+	}
+	return !now.Before(expiration.Add(-lifetime / 5))
+}
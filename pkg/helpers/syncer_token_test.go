@@ -0,0 +1,82 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetSyncerTokenHash(t *testing.T) {
+	hash := GetSyncerTokenHash("my-token")
+	if len(hash) != 64 {
+		t.Fatalf(`Expected a SHA-256 hex hash, actual %s`, hash)
+	}
+	if hash != GetSyncerTokenHash("my-token") {
+		t.Fatalf(`Expected the same hash for the same token`)
+	}
+	if hash == GetSyncerTokenHash("my-rotated-token") {
+		t.Fatalf(`Expected a different hash for a different token`)
+	}
+}
+
+func TestSetSyncerTokenStatus(t *testing.T) {
+	tokens := SetSyncerTokenStatus(nil, singaporev1alpha1.SyncerTokenStatus{LocationPath: "root:ws1", TokenHash: "hash1"})
+	tokens = SetSyncerTokenStatus(tokens, singaporev1alpha1.SyncerTokenStatus{LocationPath: "root:ws2", TokenHash: "hash2"})
+	if len(tokens) != 2 {
+		t.Fatalf(`Expected 2 token statuses, actual %d`, len(tokens))
+	}
+	rotated := SetSyncerTokenStatus(tokens, singaporev1alpha1.SyncerTokenStatus{LocationPath: "root:ws1", TokenHash: "hash3"})
+	if len(rotated) != 2 {
+		t.Fatalf(`Expected 2 token statuses, actual %d`, len(rotated))
+	}
+	if tokenStatus := FindSyncerTokenStatus(rotated, "root:ws1"); tokenStatus == nil || tokenStatus.TokenHash != "hash3" {
+		t.Fatalf(`Expected the token status of root:ws1 to be replaced, actual %v`, tokenStatus)
+	}
+	if tokenStatus := FindSyncerTokenStatus(tokens, "root:ws1"); tokenStatus == nil || tokenStatus.TokenHash != "hash1" {
+		t.Fatalf(`Expected the original token statuses to be unchanged, actual %v`, tokenStatus)
+	}
+	if tokenStatus := FindSyncerTokenStatus(rotated, "root:ws3"); tokenStatus != nil {
+		t.Fatalf(`Expected no token status for root:ws3, actual %v`, tokenStatus)
+	}
+}
+
+func TestGetSyncerTokenExpiration(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				SyncerTokenExpirationAnnotation: "2022-10-17T10:00:00Z",
+			},
+		},
+	}
+	expiration := GetSyncerTokenExpiration(secret)
+	if expiration == nil || !expiration.Equal(&metav1.Time{Time: time.Date(2022, 10, 17, 10, 0, 0, 0, time.UTC)}) {
+		t.Fatalf(`Expiration not as expected. Expected 2022-10-17T10:00:00Z, actual %v`, expiration)
+	}
+	if expiration := GetSyncerTokenExpiration(&corev1.Secret{}); expiration != nil {
+		t.Fatalf(`Expected no expiration for a secret without annotation, actual %v`, expiration)
+	}
+}
+
+func TestIsSyncerTokenExpiring(t *testing.T) {
+	now := time.Date(2022, 10, 17, 10, 0, 0, 0, time.UTC)
+	if !IsSyncerTokenExpiring(nil, time.Hour, now) {
+		t.Fatalf(`Expected a token without expiration to be renewed`)
+	}
+	expiration := metav1.NewTime(now.Add(30 * time.Minute))
+	if IsSyncerTokenExpiring(&expiration, time.Hour, now) {
+		t.Fatalf(`Expected a token expiring in 30m of a 1h lifetime not to be renewed`)
+	}
+	expiration = metav1.NewTime(now.Add(10 * time.Minute))
+	if !IsSyncerTokenExpiring(&expiration, time.Hour, now) {
+		t.Fatalf(`Expected a token expiring in 10m of a 1h lifetime to be renewed`)
+	}
+	expiration = metav1.NewTime(now.Add(-time.Minute))
+	if !IsSyncerTokenExpiring(&expiration, time.Hour, now) {
+		t.Fatalf(`Expected an expired token to be renewed`)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+)
+
+// serverVersionCachePeriod is the period the kcp server version of a workspace is reused
+const serverVersionCachePeriod = 10 * time.Minute
+
+// kcpGitVersionSeparator separates the kubernetes version from the kcp version in the
+// gitVersion reported by the kcp server, for example v1.24.3+kcp-v0.7.5
+const kcpGitVersionSeparator = "+kcp-"
+
+// GetKcpVersion returns the kcp version from the gitVersion reported by the kcp server,
+// or nil if the gitVersion doesn't embed a kcp version.
+func GetKcpVersion(gitVersion string) *utilversion.Version {
+	i := strings.Index(gitVersion, kcpGitVersionSeparator)
+	if i < 0 {
+		return nil
+	}
+	kcpVersion, err := utilversion.ParseSemantic(gitVersion[i+len(kcpGitVersionSeparator):])
+	if err != nil {
+		return nil
+	}
+	return kcpVersion
+}
+
+// GetImageVersion returns the version of the image tag, or nil if the image is referenced
+// by digest or its tag is not a semantic version, for example latest.
+func GetImageVersion(image string) *utilversion.Version {
+	if strings.Contains(image, "@") {
+		return nil
+	}
+	// The tag is after the last colon, unless that colon is the registry port
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return nil
+	}
+	imageVersion, err := utilversion.ParseSemantic(image[i+1:])
+	if err != nil {
+		return nil
+	}
+	return imageVersion
+}
+
+// CheckSyncerVersion checks that the kcp-syncer image version is compatible with the kcp server version.
+// kcp doesn't guarantee any compatibility between minor versions until v1, so the syncer and the server
+// must share the same major and minor versions.
+// It returns known false if one of the versions can't be determined.
+func CheckSyncerVersion(syncerImage string, kcpGitVersion string) (compatible bool, known bool, message string) {
+	syncerVersion := GetImageVersion(syncerImage)
+	if syncerVersion == nil {
+		return false, false, fmt.Sprintf("The version of the kcp-syncer image %s can not be determined", syncerImage)
+	}
+	kcpVersion := GetKcpVersion(kcpGitVersion)
+	if kcpVersion == nil {
+		return false, false, fmt.Sprintf("The kcp version of the kcp server version %s can not be determined", kcpGitVersion)
+	}
+	if syncerVersion.Major() != kcpVersion.Major() || syncerVersion.Minor() != kcpVersion.Minor() {
+		return false, true, fmt.Sprintf("The kcp-syncer version v%s is incompatible with the kcp server version v%s",
+			syncerVersion, kcpVersion)
+	}
+	return true, true, fmt.Sprintf("The kcp-syncer version v%s is compatible with the kcp server version v%s",
+		syncerVersion, kcpVersion)
+}
+
+// GetServerVersion returns the version of the server. Unlike the ServerVersion method of the discovery client, the
+// request is made with the context, so a cluster aware client requests the version in the kcp workspace of the
+// context, which is required by a client rejecting the requests without workspace.
+func GetServerVersion(ctx context.Context, discoveryClient discovery.DiscoveryInterface) (*version.Info, error) {
+	data, err := discoveryClient.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	info := &version.Info{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("failed to decode the server version: %w", err)
+	}
+	return info, nil
+}
+
+// ServerVersionCache caches the kcp server version per workspace, so the version is not requested on every
+// reconcile. The zero value is ready to use.
+type ServerVersionCache struct {
+	mutex    sync.Mutex
+	versions map[string]cachedServerVersion
+}
+
+type cachedServerVersion struct {
+	info      *version.Info
+	fetchedAt time.Time
+}
+
+// Get returns the server version of the workspace, requested with the context holding the workspace if it is not
+// cached or the cached one is older than serverVersionCachePeriod. Errors are not cached.
+func (c *ServerVersionCache) Get(ctx context.Context, discoveryClient discovery.DiscoveryInterface, workspace string) (*version.Info, error) {
+	c.mutex.Lock()
+	cached, ok := c.versions[workspace]
+	c.mutex.Unlock()
+	if ok && time.Since(cached.fetchedAt) < serverVersionCachePeriod {
+		return cached.info, nil
+	}
+	info, err := GetServerVersion(ctx, discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.versions == nil {
+		c.versions = map[string]cachedServerVersion{}
+	}
+	c.versions[workspace] = cachedServerVersion{info: info, fetchedAt: time.Now()}
+	return info, nil
+}
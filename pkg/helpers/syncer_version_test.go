@@ -0,0 +1,71 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+func TestCheckSyncerVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		syncerImage   string
+		kcpGitVersion string
+		compatible    bool
+		known         bool
+	}{
+		{name: "same version", syncerImage: "ghcr.io/kcp-dev/kcp/syncer:v0.7.5", kcpGitVersion: "v1.24.3+kcp-v0.7.5", compatible: true, known: true},
+		{name: "same minor", syncerImage: "ghcr.io/kcp-dev/kcp/syncer:v0.7.0", kcpGitVersion: "v1.24.3+kcp-v0.7.5", compatible: true, known: true},
+		{name: "different minor", syncerImage: "ghcr.io/kcp-dev/kcp/syncer:v0.6.1", kcpGitVersion: "v1.24.3+kcp-v0.7.5", compatible: false, known: true},
+		{name: "registry port", syncerImage: "localhost:5000/syncer:v0.7.5", kcpGitVersion: "v1.24.3+kcp-v0.7.5", compatible: true, known: true},
+		{name: "registry port without tag", syncerImage: "localhost:5000/syncer", kcpGitVersion: "v1.24.3+kcp-v0.7.5", compatible: false, known: false},
+		{name: "latest tag", syncerImage: "ghcr.io/kcp-dev/kcp/syncer:latest", kcpGitVersion: "v1.24.3+kcp-v0.7.5", compatible: false, known: false},
+		{name: "digest", syncerImage: "ghcr.io/kcp-dev/kcp/syncer@sha256:abcd", kcpGitVersion: "v1.24.3+kcp-v0.7.5", compatible: false, known: false},
+		{name: "not a kcp server", syncerImage: "ghcr.io/kcp-dev/kcp/syncer:v0.7.5", kcpGitVersion: "v1.24.3", compatible: false, known: false},
+	}
+	for _, test := range tests {
+		compatible, known, message := CheckSyncerVersion(test.syncerImage, test.kcpGitVersion)
+		if compatible != test.compatible || known != test.known {
+			t.Fatalf(`%s: compatibility not as expected. Expected %t/%t, actual %t/%t (%s)`,
+				test.name, test.compatible, test.known, compatible, known, message)
+		}
+	}
+}
+
+func TestServerVersionCache(t *testing.T) {
+	versionCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/version" {
+			http.NotFound(w, req)
+			return
+		}
+		versionCalls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"major":"1","minor":"24","gitVersion":"v1.24.3+kcp-v0.7.5"}`))
+	}))
+	defer server.Close()
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	cache := &ServerVersionCache{}
+	for _, workspace := range []string{"root:org:location1", "root:org:location1", "root:org:location2"} {
+		info, err := cache.Get(context.TODO(), discoveryClient, workspace)
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		if info.GitVersion != "v1.24.3+kcp-v0.7.5" {
+			t.Fatalf(`Server version not as expected. Expected v1.24.3+kcp-v0.7.5, actual %s`, info.GitVersion)
+		}
+	}
+	if versionCalls != 2 {
+		t.Fatalf(`Version requests not as expected. Expected 2, actual %d`, versionCalls)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// transientErrorMessages are the messages of the transient apiserver and connection errors.
+// The applier flattens the errors it returns into strings, so its errors can only be classified by message.
+var transientErrorMessages = []string{
+	"the object has been modified",
+	"the server was unable to return a response in the time allotted",
+	"the server is currently unable to handle the request",
+	"the server has received too many requests",
+	"too many requests",
+	"connection refused",
+	"connection reset by peer",
+	"i/o timeout",
+	"tls handshake timeout",
+	"unexpected eof",
+}
+
+// IsTransientError returns true if the error is likely to go away by retrying the request,
+// such as an update conflict, a timeout, a throttling or an unavailable apiserver.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if k8serrors.IsConflict(err) ||
+		k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsTooManyRequests(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, transientMessage := range transientErrorMessages {
+		if strings.Contains(message, transientMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryOnTransientError calls fn until it succeeds or fails with a not transient error, waiting between
+// the calls with the exponential backoff. The retries stop when the backoff steps are exhausted or the
+// context is done, the last error of fn is then returned.
+func RetryOnTransientError(ctx context.Context, backoff wait.Backoff, fn func() error) error {
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff, func() (bool, error) {
+		lastErr = fn()
+		switch {
+		case lastErr == nil:
+			return true, nil
+		case IsTransientError(lastErr):
+			return false, nil
+		default:
+			return false, lastErr
+		}
+	})
+	if lastErr != nil && (errors.Is(err, wait.ErrWaitTimeout) || errors.Is(err, ctx.Err())) {
+		return lastErr
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestIsTransientError(t *testing.T) {
+	secrets := schema.GroupResource{Resource: "secrets"}
+	conflict := k8serrors.NewConflict(secrets, "import", errors.New("the object has been modified; please apply your changes to the latest version and try again"))
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{name: "nil", err: nil, transient: false},
+		{name: "conflict", err: conflict, transient: true},
+		{name: "server timeout", err: k8serrors.NewServerTimeout(secrets, "create", 1), transient: true},
+		{name: "too many requests", err: k8serrors.NewTooManyRequests("throttled", 1), transient: true},
+		{name: "flattened conflict", err: fmt.Errorf("%q (%T): %v", "import_secret.yaml", conflict, conflict), transient: true},
+		{name: "flattened connection refused", err: errors.New(`"import_secret.yaml": dial tcp 10.0.0.1:443: connect: connection refused`), transient: true},
+		{name: "forbidden", err: k8serrors.NewForbidden(secrets, "import", errors.New("denied")), transient: false},
+		{name: "invalid", err: errors.New(`"import_secret.yaml": Secret "import" is invalid`), transient: false},
+	}
+	for _, test := range tests {
+		if transient := IsTransientError(test.err); transient != test.transient {
+			t.Fatalf(`%s: transient not as expected. Expected %t, actual %t`, test.name, test.transient, transient)
+		}
+	}
+}
+
+func TestRetryOnTransientError(t *testing.T) {
+	backoff := wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 2}
+	secrets := schema.GroupResource{Resource: "secrets"}
+	timeout := k8serrors.NewServerTimeout(secrets, "get", 1)
+
+	calls := 0
+	err := RetryOnTransientError(context.Background(), backoff, func() error {
+		calls++
+		if calls < 3 {
+			return timeout
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf(`Expected a success after 3 calls, actual %d calls and error %v`, calls, err)
+	}
+
+	calls = 0
+	err = RetryOnTransientError(context.Background(), backoff, func() error {
+		calls++
+		return timeout
+	})
+	if !k8serrors.IsServerTimeout(err) || calls != 3 {
+		t.Fatalf(`Expected the last transient error after 3 calls, actual %d calls and error %v`, calls, err)
+	}
+
+	calls = 0
+	notFound := k8serrors.NewNotFound(secrets, "import")
+	err = RetryOnTransientError(context.Background(), backoff, func() error {
+		calls++
+		return notFound
+	})
+	if !k8serrors.IsNotFound(err) || calls != 1 {
+		t.Fatalf(`Expected the not transient error after 1 call, actual %d calls and error %v`, calls, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = RetryOnTransientError(ctx, wait.Backoff{Steps: 10, Duration: time.Hour}, func() error {
+		calls++
+		cancel()
+		return timeout
+	})
+	if !k8serrors.IsServerTimeout(err) || calls != 1 {
+		t.Fatalf(`Expected the last transient error once the context is done, actual %d calls and error %v`, calls, err)
+	}
+}
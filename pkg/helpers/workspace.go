@@ -7,17 +7,68 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/kcp-dev/logicalcluster/v2"
 	"github.com/martinlindhe/base36"
+	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// ManagedClusterSetNameFunc returns the name of the ManagedClusterSet of a workspace. It can be replaced to
+// plug another naming strategy, the returned name must be a valid RFC 1123 label as it is also used as the
+// value of the clusterset label of the ManagedClusters.
+var ManagedClusterSetNameFunc = SanitizeManagedClusterSetName
+
+// ManagedClusterSetNameForWorkspace returns the name of the ManagedClusterSet grouping the ManagedClusters of
+// the workspace.
 func ManagedClusterSetNameForWorkspace(workspaceName string) string {
-	// TODO: THIS IS NOT SUFFICIENT AT ALL. Probably doesn't handle all illegal characters and does NOT uniquely identify a workspace.
-	// https://issues.redhat.com/browse/CMCS-158 should ensure uniqueness and ensure a valid managed cluster set name is generated
 	// TODO: incorporate kcp shard info
+	return ManagedClusterSetNameFunc(workspaceName)
+}
+
+// LegacyManagedClusterSetName returns the ManagedClusterSet name of the workspace used before the names were
+// sanitized, the ManagedClusters created by then still have it as their clusterset label value.
+func LegacyManagedClusterSetName(workspaceName string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(workspaceName, ":", "_"), "-", "_")
 }
 
+// SanitizeManagedClusterSetName is the default ManagedClusterSet naming strategy. A workspace name which is
+// already a valid RFC 1123 label is kept as is. Otherwise the name is lowercased, the invalid characters such
+// as ':' are replaced by '-' and a hash of the workspace name is appended, so two workspaces sanitized to the
+// same name still get distinct ManagedClusterSets. The name is truncated to fit the 63 characters of a label.
+func SanitizeManagedClusterSetName(workspaceName string) string {
+	if len(validation.IsDNS1123Label(workspaceName)) == 0 {
+		return workspaceName
+	}
+	sanitized := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(workspaceName))
+
+	nameHash := sha256.Sum224([]byte(workspaceName))
+	suffix := strings.ToLower(base36.EncodeBytes(nameHash[:]))[:8]
+	maxLength := validation.DNS1123LabelMaxLength - len(suffix) - 1
+	if len(sanitized) > maxLength {
+		sanitized = sanitized[:maxLength]
+	}
+	sanitized = strings.Trim(sanitized, "-")
+	if len(sanitized) == 0 {
+		return suffix
+	}
+	return fmt.Sprintf("%s-%s", sanitized, suffix)
+}
+
+// GetLocationPath returns the fully qualified path of a location workspace. A location with a ':' or the root
+// workspace is already fully qualified, otherwise it is a child of the given RegisteredCluster workspace.
+func GetLocationPath(clusterName string, location string) string {
+	if strings.Contains(location, ":") || location == "root" || len(clusterName) == 0 {
+		return location
+	}
+	return logicalcluster.New(clusterName).Join(location).String()
+}
+
 func GetSyncerPrefix() string {
 	return "kcp-syncer"
 }
@@ -29,6 +80,37 @@ func GetSyncerName(syncTarget *unstructured.Unstructured) string { // Should be
 	return fmt.Sprintf("%s-%s-%s", GetSyncerPrefix(), syncTarget.GetName(), base36hash[:8])
 }
 
-func GetSyncerServiceAccountName() string {
-	return "kcp-syncer-sa"
+// GetMaxRegisteredClusterNameLength returns the longest RegisteredCluster name whose kcp-syncer name, which is
+// also the name of the kcp-syncer namespace on the managed cluster, fits in a 63 characters RFC 1123 label.
+// The SyncTarget is created with the <name>- generateName, the apiserver adds a 5 characters suffix.
+func GetMaxRegisteredClusterNameLength() int {
+	// kcp-syncer-<name>-<5 characters suffix>-<8 characters hash>
+	return validation.DNS1123LabelMaxLength - len(GetSyncerPrefix()) - 8 - 2 - 6
+}
+
+// ValidateRegisteredClusterName returns an error if the RegisteredCluster name would overflow the name of the
+// resources deployed for its kcp-syncer.
+func ValidateRegisteredClusterName(name string) error {
+	if maxLength := GetMaxRegisteredClusterNameLength(); len(name) > maxLength {
+		return fmt.Errorf("RegisteredCluster name %s is too long (%d characters), the name must be at most %d characters "+
+			"as it is part of the kcp-syncer name %s-<name>-<suffix>-<hash>", name, len(name), maxLength, GetSyncerPrefix())
+	}
+	return nil
+}
+
+// IsSyncerDeployed returns true if the kcp-syncer of the RegisteredCluster locations is deployed on the cluster.
+func IsSyncerDeployed(regCluster *singaporev1alpha1.RegisteredCluster) bool {
+	return regCluster.Spec.DeploySyncer == nil || *regCluster.Spec.DeploySyncer
+}
+
+// GetSyncerServiceAccountName returns the name of the kcp-syncer ServiceAccount of a SyncTarget. Each kcp-syncer
+// gets its own ServiceAccount, so the RegisteredClusters sharing a location workspace don't share a token.
+func GetSyncerServiceAccountName(syncTargetName string) string {
+	return fmt.Sprintf("%s-%s-sa", GetSyncerPrefix(), syncTargetName)
+}
+
+// GetSyncerBoundTokenSecretName returns the name of the secret holding the bound token minted for the kcp-syncer
+// ServiceAccount, when the ServiceAccount has no legacy token secret.
+func GetSyncerBoundTokenSecretName(serviceAccountName string) string {
+	return serviceAccountName + "-bound-token"
 }
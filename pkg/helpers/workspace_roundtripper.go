@@ -0,0 +1,37 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+	"k8s.io/client-go/rest"
+)
+
+// ErrNoWorkspace is returned by a compute client call made with a context without a kcp workspace.
+var ErrNoWorkspace = errors.New("no kcp workspace in the context")
+
+// requireWorkspaceRoundTripper fails the requests whose context has no kcp workspace.
+type requireWorkspaceRoundTripper struct {
+	delegate http.RoundTripper
+}
+
+func (rt *requireWorkspaceRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if cluster, ok := logicalcluster.ClusterFromContext(req.Context()); !ok || cluster.Empty() {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoWorkspace, req.Method, req.URL.Path)
+	}
+	return rt.delegate.RoundTrip(req)
+}
+
+// NewRequireWorkspaceConfig returns a copy of the config whose clients fail the calls made without a kcp
+// workspace in the context, so a call missing logicalcluster.WithCluster doesn't silently target the root workspace.
+func NewRequireWorkspaceConfig(cfg *rest.Config) *rest.Config {
+	copyCfg := rest.CopyConfig(cfg)
+	copyCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &requireWorkspaceRoundTripper{delegate: rt}
+	})
+	return copyCfg
+}
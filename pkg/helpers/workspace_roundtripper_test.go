@@ -0,0 +1,43 @@
+// Copyright Red Hat
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v2"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRequireWorkspaceRoundTripper(t *testing.T) {
+	rt := &requireWorkspaceRoundTripper{
+		delegate: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK}, nil
+		}),
+	}
+
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://kcp/api/v1/namespaces/default/secrets", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if _, err := rt.RoundTrip(req); !errors.Is(err, ErrNoWorkspace) {
+		t.Fatalf(`Error not as expected. Expected %s, actual %v`, ErrNoWorkspace, err)
+	}
+
+	req = req.WithContext(logicalcluster.WithCluster(context.TODO(), logicalcluster.New("root:compute")))
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf(`Status code not as expected. Expected %d, actual %d`, http.StatusOK, resp.StatusCode)
+	}
+}
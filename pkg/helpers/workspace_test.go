@@ -3,7 +3,12 @@
 package helpers
 
 import (
+	"strings"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apiserver/pkg/storage/names"
 )
 
 func TestManagedClusterSetNameForWorkspace(t *testing.T) {
@@ -13,3 +18,120 @@ func TestManagedClusterSetNameForWorkspace(t *testing.T) {
 		t.Fatalf(`ManagedClusterSet name is not as expected. Expected %s, actual %s`, workspaceName, name)
 	}
 }
+
+func TestManagedClusterSetNameForWorkspaceCustom(t *testing.T) {
+	defer func(f func(string) string) { ManagedClusterSetNameFunc = f }(ManagedClusterSetNameFunc)
+	ManagedClusterSetNameFunc = func(workspaceName string) string { return "custom" }
+	if name := ManagedClusterSetNameForWorkspace("root:compute"); name != "custom" {
+		t.Fatalf(`ManagedClusterSet name is not as expected. Expected custom, actual %s`, name)
+	}
+}
+
+func TestLegacyManagedClusterSetName(t *testing.T) {
+	if name := LegacyManagedClusterSetName("root:my-org:compute"); name != "root_my_org_compute" {
+		t.Fatalf(`ManagedClusterSet name is not as expected. Expected root_my_org_compute, actual %s`, name)
+	}
+}
+
+func TestSanitizeManagedClusterSetName(t *testing.T) {
+	cases := []struct {
+		name          string
+		workspaceName string
+		prefix        string
+	}{
+		{
+			name:          "valid name",
+			workspaceName: "root-compute",
+			prefix:        "root-compute",
+		},
+		{
+			name:          "colons",
+			workspaceName: "root:org:compute",
+			prefix:        "root-org-compute-",
+		},
+		{
+			name:          "uppercase",
+			workspaceName: "Root:JaneDoe",
+			prefix:        "root-janedoe-",
+		},
+		{
+			name:          "leading invalid characters",
+			workspaceName: "_root:compute",
+			prefix:        "root-compute-",
+		},
+		{
+			name:          "too long",
+			workspaceName: "root:" + strings.Repeat("a", 63),
+			prefix:        "root-" + strings.Repeat("a", 49) + "-",
+		},
+		{
+			name:          "valid name too long",
+			workspaceName: strings.Repeat("b", 64),
+			prefix:        strings.Repeat("b", 54) + "-",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			name := SanitizeManagedClusterSetName(c.workspaceName)
+			if errs := validation.IsDNS1123Label(name); len(errs) != 0 {
+				t.Fatalf("ManagedClusterSet name %s is not a valid label: %v", name, errs)
+			}
+			if !strings.HasPrefix(name, c.prefix) {
+				t.Fatalf("ManagedClusterSet name is not as expected. Expected prefix %s, actual %s", c.prefix, name)
+			}
+			if name != SanitizeManagedClusterSetName(c.workspaceName) {
+				t.Fatalf("ManagedClusterSet name of %s is not stable", c.workspaceName)
+			}
+		})
+	}
+
+	// Workspaces sanitized to the same name get distinct ManagedClusterSets
+	if SanitizeManagedClusterSetName("root:a-b") == SanitizeManagedClusterSetName("root-a:b") {
+		t.Fatal("ManagedClusterSet names of distinct workspaces must differ")
+	}
+}
+
+func TestGetLocationPath(t *testing.T) {
+	path := GetLocationPath("root:compute", "root:org:location")
+	if path != "root:org:location" {
+		t.Fatalf(`Location path is not as expected. Expected root:org:location, actual %s`, path)
+	}
+	path = GetLocationPath("root:compute", "location")
+	if path != "root:compute:location" {
+		t.Fatalf(`Location path is not as expected. Expected root:compute:location, actual %s`, path)
+	}
+}
+
+func TestValidateRegisteredClusterName(t *testing.T) {
+	maxLength := GetMaxRegisteredClusterNameLength()
+	if maxLength != 37 {
+		t.Fatalf("Max RegisteredCluster name length not as expected. Expected 37, actual %d", maxLength)
+	}
+	// The SyncTarget name is generated as in syncSyncTarget, from the <regcluster>- generateName
+	syncTarget := &unstructured.Unstructured{}
+	syncTarget.SetName(names.SimpleNameGenerator.GenerateName(strings.Repeat("a", maxLength) + "-"))
+	syncTarget.SetUID("d6d7b7e8-4d5f-4a4f-9d3e-2b7c8e9f0a1b")
+	if err := ValidateRegisteredClusterName(strings.Repeat("a", maxLength)); err != nil {
+		t.Fatalf("RegisteredCluster name of %d characters must be valid: %v", maxLength, err)
+	}
+	if errs := validation.IsDNS1123Label(GetSyncerName(syncTarget)); len(errs) != 0 {
+		t.Fatalf("syncer name %s is not a valid label: %v", GetSyncerName(syncTarget), errs)
+	}
+
+	syncTarget.SetName(names.SimpleNameGenerator.GenerateName(strings.Repeat("a", maxLength+1) + "-"))
+	if err := ValidateRegisteredClusterName(strings.Repeat("a", maxLength+1)); err == nil {
+		t.Fatalf("RegisteredCluster name of %d characters must be rejected", maxLength+1)
+	}
+	if errs := validation.IsDNS1123Label(GetSyncerName(syncTarget)); len(errs) == 0 {
+		t.Fatalf("syncer name %s is expected to overflow", GetSyncerName(syncTarget))
+	}
+}
+
+func TestGetSyncerServiceAccountName(t *testing.T) {
+	if GetSyncerServiceAccountName("cluster1") == GetSyncerServiceAccountName("cluster2") {
+		t.Fatal("kcp-syncer service account names of distinct synctargets must differ")
+	}
+	if name := GetSyncerBoundTokenSecretName(GetSyncerServiceAccountName("cluster1")); name != "kcp-syncer-cluster1-sa-bound-token" {
+		t.Fatalf("bound token secret name is not as expected. Expected kcp-syncer-cluster1-sa-bound-token, actual %s", name)
+	}
+}
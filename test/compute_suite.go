@@ -65,6 +65,8 @@ const (
 
 	// The compute workspace (where RegisteredCluster is created)
 	ComputeWorkspace string = "my-compute-ws"
+	// The second compute workspace, used to check RegisteredClusters of several workspaces are processed independently
+	ComputeWorkspace2 string = "my-compute-ws2"
 	// The location workspace (where SyncTarget is generated)
 	LocationWorkspace1 string = "my-location-ws1"
 	// The location workspace where SyncTarget is generated
@@ -79,6 +81,7 @@ const (
 	OrganizationWorkspace string = "root:" + ComputeOrganization
 	// The compute cluster workspace
 	AbsoluteComputeWorkspace   string = OrganizationWorkspace + ":" + ComputeWorkspace
+	AbsoluteComputeWorkspace2  string = OrganizationWorkspace + ":" + ComputeWorkspace2
 	AbsoluteLocationWorkspace1 string = OrganizationWorkspace + ":" + LocationWorkspace1
 	AbsoluteLocationWorkspace2 string = OrganizationWorkspace + ":" + LocationWorkspace2
 	// The directory for test environment assets
@@ -101,6 +104,8 @@ var (
 	computeAdminKubconfigData  []byte
 	// The compute kubeconfig file
 	AdminComputeKubeconfigFile string = ".kcp/admin.kubeconfig"
+	// The cluster aware compute admin client, the workspace is taken from the context
+	ComputeAdminRuntimeClient client.Client
 )
 
 var apibindingGVR = schema.GroupVersionResource{
@@ -545,6 +550,21 @@ func SetupCompute(scheme *runtime.Scheme, controllerNamespace, scriptsPath strin
 			return nil
 		}, 60, 3).Should(gomega.BeNil())
 	})
+	// Create the second compute workspace on compute server without entering in it
+	ginkgo.By(fmt.Sprintf("creation of cluster workspace %s", ComputeWorkspace2), func() {
+		gomega.Eventually(func() error {
+			return CreateWorkspace(ComputeWorkspace2, OrganizationWorkspace, adminComputeKubeconfigFile, false)
+		}, 60, 3).Should(gomega.BeNil())
+	})
+
+	// Create the APIBinding in the second cluster workspace
+	ginkgo.By(fmt.Sprintf("apply APIBinding on workspace %s", ComputeWorkspace2), func() {
+		computeContext2 := logicalcluster.WithCluster(context.Background(), logicalcluster.New(AbsoluteComputeWorkspace2))
+		gomega.Eventually(func() error {
+			return CreateAPIBinding(computeContext2, computeAdminApplierBuilder, readerResources)
+		}, 60, 3).Should(gomega.BeNil())
+	})
+
 	// Create compute workspace on compute server and enter in the ws
 	ginkgo.By(fmt.Sprintf("creation of cluster workspace %s", ComputeWorkspace), func() {
 		gomega.Eventually(func() error {
@@ -568,6 +588,9 @@ func SetupCompute(scheme *runtime.Scheme, controllerNamespace, scriptsPath strin
 	computeRuntimeWorkspaceClient, err = client.New(computeRestWorkspaceConfig, client.Options{Scheme: scheme})
 	gomega.Expect(err).ToNot(gomega.HaveOccurred())
 
+	ComputeAdminRuntimeClient, err = client.New(computeAdminRestConfig, client.Options{Scheme: scheme})
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
 	kubeconfig, err := ioutil.ReadFile(filepath.Clean(saComputeKubeconfigFileAbs))
 	gomega.Expect(err).ToNot(gomega.HaveOccurred())
 	controllerSAKubeConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
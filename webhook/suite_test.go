@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"path/filepath"
 	"testing"
+	"time"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -117,3 +118,94 @@ var _ = Describe("Process clusterRegistrar: ", func() {
 		})
 	})
 })
+
+var _ = Describe("Process clusterRegistrar leader election: ", func() {
+	It("Validate clusterRegistrar leader election update", func() {
+		registeredClusterAdmissionHook := &RegisteredClusterAdmissionHook{}
+		clusterRegistrar := &singaporev1alpha1.ClusterRegistrar{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-reg",
+			},
+			Spec: singaporev1alpha1.ClusterRegistrarSpec{
+				OperatorLeaderElection: &singaporev1alpha1.LeaderElectionConfig{
+					LeaseDuration: &metav1.Duration{Duration: 30 * time.Second},
+					RenewDeadline: &metav1.Duration{Duration: 20 * time.Second},
+				},
+			},
+		}
+		getAdmissionRequest := func() *admissionv1beta1.AdmissionRequest {
+			clusterRegistrarJson, err := json.Marshal(clusterRegistrar)
+			Expect(err).To(BeNil())
+			return &admissionv1beta1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource(helpers.GvrCR),
+				Operation: admissionv1beta1.Update,
+				Object: runtime.RawExtension{
+					Raw: clusterRegistrarJson,
+				},
+			}
+		}
+		By("Validate a renew deadline shorter than the lease duration", func() {
+			admissionResponse := registeredClusterAdmissionHook.Validate(getAdmissionRequest())
+			Expect(admissionResponse.Allowed).To(BeTrue())
+		})
+		By("Validate a renew deadline longer than the lease duration", func() {
+			clusterRegistrar.Spec.OperatorLeaderElection.RenewDeadline.Duration = time.Minute
+			admissionResponse := registeredClusterAdmissionHook.Validate(getAdmissionRequest())
+			Expect(admissionResponse.Allowed).To(BeFalse())
+			Expect(admissionResponse.Result.Message).To(ContainSubstring("spec.operatorLeaderElection is invalid"))
+		})
+		By("Validate an invalid configuration with the leader election disabled", func() {
+			clusterRegistrar.Spec.OperatorLeaderElection.Disabled = true
+			admissionResponse := registeredClusterAdmissionHook.Validate(getAdmissionRequest())
+			Expect(admissionResponse.Allowed).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("Process registeredCluster: ", func() {
+	It("Validate registeredCluster location update", func() {
+		registeredClusterAdmissionHook := &RegisteredClusterAdmissionHook{}
+		oldRegCluster := &singaporev1alpha1.RegisteredCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "registered-cluster",
+				Namespace: "rc-ws",
+			},
+			Spec: singaporev1alpha1.RegisteredClusterSpec{
+				Location: []string{test.AbsoluteLocationWorkspace1},
+			},
+		}
+		regCluster := oldRegCluster.DeepCopy()
+		regCluster.Spec.Location = []string{test.AbsoluteLocationWorkspace2}
+		getAdmissionRequest := func() *admissionv1beta1.AdmissionRequest {
+			oldRegClusterJson, err := json.Marshal(oldRegCluster)
+			Expect(err).To(BeNil())
+			regClusterJson, err := json.Marshal(regCluster)
+			Expect(err).To(BeNil())
+			return &admissionv1beta1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource(helpers.GvrRC),
+				Operation: admissionv1beta1.Update,
+				Object: runtime.RawExtension{
+					Raw: regClusterJson,
+				},
+				OldObject: runtime.RawExtension{
+					Raw: oldRegClusterJson,
+				},
+			}
+		}
+		By("Validate the location update before the import", func() {
+			admissionResponse := registeredClusterAdmissionHook.Validate(getAdmissionRequest())
+			Expect(admissionResponse.Allowed).To(BeTrue())
+		})
+		By("Validate the location update once the import command is generated", func() {
+			oldRegCluster.Status.ImportCommandRef.Name = "registered-cluster-import"
+			admissionResponse := registeredClusterAdmissionHook.Validate(getAdmissionRequest())
+			Expect(admissionResponse.Allowed).To(BeFalse())
+			Expect(admissionResponse.Result.Message).To(ContainSubstring("spec.location can not be changed"))
+		})
+		By("Validate an update keeping the location once the import command is generated", func() {
+			regCluster.Spec.Location = oldRegCluster.Spec.Location
+			admissionResponse := registeredClusterAdmissionHook.Validate(getAdmissionRequest())
+			Expect(admissionResponse.Allowed).To(BeTrue())
+		})
+	})
+})
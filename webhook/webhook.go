@@ -16,14 +16,17 @@ import (
 	genericapiserver "k8s.io/apiserver/pkg/server"
 
 	singaporev1alpha1 "github.com/stolostron/compute-operator/api/singapore/v1alpha1"
+	"github.com/stolostron/compute-operator/pkg/helpers"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+	clusterapiv1 "open-cluster-management.io/api/cluster/v1"
 )
 
 const (
@@ -118,17 +121,77 @@ func (a *RegisteredClusterAdmissionHook) ValidateRegisteredCluster(admissionSpec
 	case admissionv1beta1.Create:
 		klog.V(4).Info("Validate RegisteredCluster create ")
 
-		if len(regCluster.Name) > 50 {
+		if err := helpers.ValidateRegisteredClusterName(regCluster.Name); err != nil {
 			status.Allowed = false
 			status.Result = &metav1.Status{
 				Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden,
-				Message: "RegisteredCluster name is too long (max 50 characters)",
+				Message: err.Error(),
 			}
 			return status
 		}
 
 		status.Allowed = true
 		return status
+	case admissionv1beta1.Update:
+		klog.V(4).Info("Validate RegisteredCluster update ")
+
+		oldRegCluster := &singaporev1alpha1.RegisteredCluster{}
+		if err := json.Unmarshal(admissionSpec.OldObject.Raw, oldRegCluster); err != nil {
+			status.Allowed = false
+			status.Result = &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: err.Error(),
+			}
+			return status
+		}
+
+		if status := validateLocationUpdate(oldRegCluster, regCluster); !status.Allowed {
+			return status
+		}
+	}
+	status.Allowed = true
+	return status
+}
+
+// validateLocationUpdate rejects a change of the locations once the import of the cluster started, the kcp-syncer
+// deployed for the previous locations would be stranded in their workspaces.
+func validateLocationUpdate(oldRegCluster, regCluster *singaporev1alpha1.RegisteredCluster) *admissionv1beta1.AdmissionResponse {
+	status := &admissionv1beta1.AdmissionResponse{}
+	if equality.Semantic.DeepEqual(oldRegCluster.Spec.Location, regCluster.Spec.Location) {
+		status.Allowed = true
+		return status
+	}
+	joined, _ := helpers.GetConditionStatus(oldRegCluster.Status.Conditions, clusterapiv1.ManagedClusterConditionJoined)
+	if len(oldRegCluster.Status.ImportCommandRef.Name) != 0 || joined == metav1.ConditionTrue {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden,
+			Message: "RegisteredCluster spec.location can not be changed once the import command is generated or the cluster joined, " +
+				"the kcp-syncer of the previous locations would be stranded. Delete and recreate the RegisteredCluster to change its locations",
+		}
+		return status
+	}
+	status.Allowed = true
+	return status
+}
+
+// validateLeaderElection checks that the renew deadline of the compute-operator leader election is shorter
+// than its lease duration, the compute-operator would not start otherwise.
+func validateLeaderElection(clusterRegistrar *singaporev1alpha1.ClusterRegistrar) *admissionv1beta1.AdmissionResponse {
+	status := &admissionv1beta1.AdmissionResponse{}
+	leaderElection := clusterRegistrar.Spec.OperatorLeaderElection
+	if leaderElection == nil || leaderElection.Disabled {
+		status.Allowed = true
+		return status
+	}
+	leaseDuration, renewDeadline := helpers.GetLeaderElectionDurations(leaderElection)
+	if err := helpers.ValidateLeaderElectionDurations(leaseDuration, renewDeadline); err != nil {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden,
+			Message: "ClusterRegistrar spec.operatorLeaderElection is invalid: " + err.Error(),
+		}
+		return status
 	}
 	status.Allowed = true
 	return status
@@ -151,6 +214,16 @@ func (a *RegisteredClusterAdmissionHook) ValidateClusterRegistrar(admissionSpec
 
 	klog.V(4).Infof("Validate webhook for ClusterRegistrar name: %s", clusterRegistrar.Name)
 
+	if status := validateLeaderElection(clusterRegistrar); !status.Allowed {
+		return status
+	}
+
+	// Only the creation of a second ClusterRegistrar is rejected
+	if admissionSpec.Operation == admissionv1beta1.Update {
+		status.Allowed = true
+		return status
+	}
+
 	l, err := a.ClusterRegistrarClient.List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		status.Allowed = false